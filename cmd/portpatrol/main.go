@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/containeroo/portpatrol/internal/app"
+	"github.com/containeroo/portpatrol/internal/redact"
 )
 
 const version string = "v0.5.13"
@@ -15,7 +16,7 @@ func main() {
 	ctx := context.Background()
 
 	if err := app.Run(ctx, version, os.Args[1:], os.Stdout); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, redact.Error(err))
 		os.Exit(1)
 	}
 }
@@ -7,11 +7,14 @@ import (
 	"io"
 	"os"
 	"os/signal"
+	"sort"
 	"syscall"
+	"text/tabwriter"
 
 	"github.com/containeroo/portpatrol/internal/config"
 	"github.com/containeroo/portpatrol/internal/factory"
 	"github.com/containeroo/portpatrol/internal/logging"
+	"github.com/containeroo/portpatrol/internal/redact"
 	"github.com/containeroo/portpatrol/internal/wait"
 	"golang.org/x/sync/errgroup"
 )
@@ -26,7 +29,7 @@ func Run(ctx context.Context, version string, args []string, output io.Writer) e
 	parsedFlags, err := config.ParseFlags(args, version, output)
 	if err != nil {
 		if errors.Is(err, &config.HelpRequested{}) {
-			fmt.Fprint(output, err.Error())
+			fmt.Fprint(output, redact.String(err.Error()))
 			return nil
 		}
 		return fmt.Errorf("configuration error: %w", err)
@@ -42,6 +45,11 @@ func Run(ctx context.Context, version string, args []string, output io.Writer) e
 		return errors.New("configuration error: no checkers configured")
 	}
 
+	if parsedFlags.DryRun {
+		printTargets(output, checkers)
+		return nil
+	}
+
 	logger := logging.SetupLogger(version, output)
 
 	// Run checkers concurrently
@@ -64,3 +72,25 @@ func Run(ctx context.Context, version string, args []string, output io.Writer) e
 
 	return nil
 }
+
+// printTargets writes the resolved targets to the output without running any checks.
+func printTargets(output io.Writer, checkers []factory.CheckerWithInterval) {
+	sort.Slice(checkers, func(i, j int) bool {
+		if checkers[i].Checker.Type() != checkers[j].Checker.Type() {
+			return checkers[i].Checker.Type() < checkers[j].Checker.Type()
+		}
+		return checkers[i].Checker.Name() < checkers[j].Checker.Name()
+	})
+
+	tw := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tNAME\tADDRESS\tINTERVAL")
+	for _, chk := range checkers {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			chk.Checker.Type(),
+			chk.Checker.Name(),
+			redact.String(chk.Checker.Address()),
+			chk.Interval,
+		)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,111 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+)
+
+// AddressResult holds the outcome of checking a single resolved IP address.
+type AddressResult struct {
+	IP  string
+	Err error
+}
+
+// AddressResults holds the outcomes of checking every resolved IP address of a target.
+type AddressResults []AddressResult
+
+// String returns a summary of the results, e.g. "10.0.0.1=ok, 10.0.0.2=connection refused".
+func (r AddressResults) String() string {
+	parts := make([]string, 0, len(r))
+	for _, res := range r {
+		if res.Err == nil {
+			parts = append(parts, res.IP+"=ok")
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%s", res.IP, res.Err))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Reachable returns the number of addresses that passed the check.
+func (r AddressResults) Reachable() int {
+	reachable := 0
+	for _, res := range r {
+		if res.Err == nil {
+			reachable++
+		}
+	}
+	return reachable
+}
+
+// lookupIPs resolves host to all its IP addresses. An IP literal is returned as is.
+func lookupIPs(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve '%s': %w", host, err)
+	}
+
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		ips = append(ips, addr.IP)
+	}
+	return ips, nil
+}
+
+// checkAllAddresses runs check for every IP and returns the per-IP results. It returns an error if fewer
+// than minReachable addresses pass; a minReachable of zero requires every address to pass.
+// If parallel is false, the addresses are checked one after another.
+func checkAllAddresses(ctx context.Context, ips []net.IP, minReachable int, parallel bool, check func(ctx context.Context, ip net.IP) error) (AddressResults, error) {
+	results := make(AddressResults, len(ips))
+
+	var wg sync.WaitGroup
+	for i, ip := range ips {
+		results[i].IP = ip.String()
+		if !parallel {
+			results[i].Err = check(ctx, ip)
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, ip net.IP) {
+			defer wg.Done()
+			results[i].Err = check(ctx, ip)
+		}(i, ip)
+	}
+	wg.Wait()
+
+	required := minReachable
+	if required <= 0 {
+		required = len(ips)
+	}
+
+	if reachable := results.Reachable(); reachable < required {
+		return results, fmt.Errorf("%d of %d addresses reachable, %d required: %s", reachable, len(ips), required, results)
+	}
+
+	return results, nil
+}
+
+// lookupTargetIPs resolves host to all its IP addresses, honoring a "host:port" override from overrides.
+func lookupTargetIPs(ctx context.Context, host, port string, overrides map[string]string) ([]net.IP, error) {
+	if ip, ok := resolveOverride(overrides, host, port); ok {
+		return []net.IP{net.ParseIP(ip)}, nil
+	}
+	return lookupIPs(ctx, host)
+}
+
+// addressDetails returns the per-address results as log attributes, or nil if no results are available.
+func addressDetails(results AddressResults) []slog.Attr {
+	if results == nil {
+		return nil
+	}
+	return []slog.Attr{slog.String("addresses", results.String())}
+}
@@ -0,0 +1,74 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckAllAddresses(t *testing.T) {
+	t.Parallel()
+
+	ips := []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2"), net.ParseIP("fd00::3")}
+	failSecond := func(ctx context.Context, ip net.IP) error {
+		if ip.Equal(net.ParseIP("10.0.0.2")) {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	t.Run("All addresses reachable", func(t *testing.T) {
+		t.Parallel()
+
+		results, err := checkAllAddresses(context.Background(), ips, 0, true, func(ctx context.Context, ip net.IP) error {
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "10.0.0.1=ok, 10.0.0.2=ok, fd00::3=ok", results.String())
+	})
+
+	t.Run("Partially down requires all addresses", func(t *testing.T) {
+		t.Parallel()
+
+		results, err := checkAllAddresses(context.Background(), ips, 0, true, failSecond)
+		assert.EqualError(t, err, "2 of 3 addresses reachable, 3 required: 10.0.0.1=ok, 10.0.0.2=connection refused, fd00::3=ok")
+		assert.Equal(t, 2, results.Reachable())
+	})
+
+	t.Run("Partially down meets minimum", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := checkAllAddresses(context.Background(), ips, 2, false, failSecond)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Minimum exceeds resolved addresses", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := checkAllAddresses(context.Background(), ips[:1], 2, true, failSecond)
+		assert.EqualError(t, err, "1 of 1 addresses reachable, 2 required: 10.0.0.1=ok")
+	})
+}
+
+func TestLookupTargetIPs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("IP literal", func(t *testing.T) {
+		t.Parallel()
+
+		ips, err := lookupTargetIPs(context.Background(), "127.0.0.1", "80", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []net.IP{net.ParseIP("127.0.0.1")}, ips)
+	})
+
+	t.Run("Resolve override", func(t *testing.T) {
+		t.Parallel()
+
+		ips, err := lookupTargetIPs(context.Background(), "API.portpatrol.test", "443", map[string]string{"api.portpatrol.test:443": "10.0.0.12"})
+		assert.NoError(t, err)
+		assert.Equal(t, []net.IP{net.ParseIP("10.0.0.12")}, ips)
+	})
+}
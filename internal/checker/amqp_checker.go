@@ -0,0 +1,470 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAMQPTimeout  time.Duration = 2 * time.Second
+	defaultAMQPUsername string        = "guest"
+	defaultAMQPPassword string        = "guest"
+	defaultAMQPVHost    string        = "/"
+	maxAMQPFrameSize    int           = 128 * 1024 // Largest frame accepted, RabbitMQ uses 128 KiB by default
+
+	amqpFrameMethod    byte = 1
+	amqpFrameHeartbeat byte = 8
+	amqpFrameEnd       byte = 0xCE
+)
+
+// amqpProtocolHeader starts an AMQP 0-9-1 connection.
+var amqpProtocolHeader = []byte("AMQP\x00\x00\x09\x01")
+
+// AMQP class and method IDs used by the AMQPChecker.
+const (
+	amqpConnection uint16 = 10
+	amqpChannel    uint16 = 20
+	amqpQueue      uint16 = 50
+
+	amqpConnectionStart   uint16 = 10
+	amqpConnectionStartOk uint16 = 11
+	amqpConnectionTune    uint16 = 30
+	amqpConnectionTuneOk  uint16 = 31
+	amqpConnectionOpen    uint16 = 40
+	amqpConnectionOpenOk  uint16 = 41
+	amqpConnectionClose   uint16 = 50
+	amqpConnectionCloseOk uint16 = 51
+	amqpChannelOpen       uint16 = 10
+	amqpChannelOpenOk     uint16 = 11
+	amqpChannelClose      uint16 = 40
+	amqpQueueDeclare      uint16 = 10
+	amqpQueueDeclareOk    uint16 = 11
+)
+
+// AMQPChecker implements the Checker interface by performing the AMQP 0-9-1 connection handshake.
+type AMQPChecker struct {
+	name      string
+	address   string
+	timeout   time.Duration // Timeout for connecting and the whole handshake
+	username  string
+	password  string
+	vhost     string // Virtual host opened after authenticating
+	queue     string // Queue that must exist, declared passively, not checked if empty
+	tlsConfig *tls.Config
+	lastQueue *amqpQueueState
+}
+
+// amqpQueueState holds the counters of a passively declared queue.
+type amqpQueueState struct {
+	messages  uint32
+	consumers uint32
+}
+
+func (c *AMQPChecker) Address() string { return c.address }
+func (c *AMQPChecker) Name() string    { return c.name }
+func (c *AMQPChecker) Type() string    { return AMQP.String() }
+
+// Details returns the number of messages and consumers of the queue declared in the last check.
+func (c *AMQPChecker) Details() []slog.Attr {
+	if c.lastQueue == nil {
+		return nil
+	}
+	return []slog.Attr{
+		slog.Int("messages", int(c.lastQueue.messages)),
+		slog.Int("consumers", int(c.lastQueue.consumers)),
+	}
+}
+
+func (c *AMQPChecker) Check(ctx context.Context) error {
+	c.lastQueue = nil
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := dialTCP(ctx, c.address, c.tlsConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("failed to set deadline: %w", err)
+	}
+
+	r := bufio.NewReader(conn)
+	if err := c.handshake(conn, r); err != nil {
+		return err
+	}
+
+	if c.queue != "" {
+		if err := c.declareQueue(conn, r); err != nil {
+			return err
+		}
+	}
+
+	// The broker is ready at this point, so a missing Close-Ok is not an error
+	var e amqpEncoder
+	e.short(200)
+	e.shortstr("OK")
+	e.short(0)
+	e.short(0)
+	if err := writeAMQPMethod(conn, 0, amqpConnection, amqpConnectionClose, e.buf); err == nil {
+		_, _ = expectAMQPMethod(r, amqpConnection, amqpConnectionCloseOk)
+	}
+
+	return nil
+}
+
+// handshake negotiates the connection, authenticates with PLAIN and opens the virtual host.
+func (c *AMQPChecker) handshake(conn net.Conn, r *bufio.Reader) error {
+	if _, err := conn.Write(amqpProtocolHeader); err != nil {
+		return fmt.Errorf("failed to send protocol header: %w", err)
+	}
+
+	d, err := expectAMQPMethod(r, amqpConnection, amqpConnectionStart)
+	if err != nil {
+		return err
+	}
+	d.octet() // version-major
+	d.octet() // version-minor
+	d.table() // server-properties
+	mechanisms := d.longstr()
+	if d.err != nil {
+		return d.err
+	}
+	if !slices.Contains(strings.Fields(mechanisms), "PLAIN") {
+		return fmt.Errorf("server does not support the PLAIN mechanism: %q", mechanisms)
+	}
+
+	var e amqpEncoder
+	e.table(func(props *amqpEncoder) {
+		props.shortstr("product")
+		props.octet('S')
+		props.longstr("portpatrol")
+		// Makes RabbitMQ report failed authentication with Connection.Close instead of closing the socket
+		props.shortstr("capabilities")
+		props.octet('F')
+		props.table(func(capabilities *amqpEncoder) {
+			capabilities.shortstr("authentication_failure_close")
+			capabilities.octet('t')
+			capabilities.octet(1)
+		})
+	})
+	e.shortstr("PLAIN")
+	e.longstr("\x00" + c.username + "\x00" + c.password)
+	e.shortstr("en_US")
+	if err := writeAMQPMethod(conn, 0, amqpConnection, amqpConnectionStartOk, e.buf); err != nil {
+		return fmt.Errorf("failed to send Start-Ok: %w", err)
+	}
+
+	d, err = expectAMQPMethod(r, amqpConnection, amqpConnectionTune)
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	channelMax := d.short()
+	frameMax := d.long()
+	if d.err != nil {
+		return d.err
+	}
+
+	e = amqpEncoder{}
+	e.short(channelMax)
+	e.long(frameMax)
+	e.short(0) // Heartbeats are not needed for a single check
+	if err := writeAMQPMethod(conn, 0, amqpConnection, amqpConnectionTuneOk, e.buf); err != nil {
+		return fmt.Errorf("failed to send Tune-Ok: %w", err)
+	}
+
+	e = amqpEncoder{}
+	e.shortstr(c.vhost)
+	e.shortstr("") // reserved-1
+	e.octet(0)     // reserved-2
+	if err := writeAMQPMethod(conn, 0, amqpConnection, amqpConnectionOpen, e.buf); err != nil {
+		return fmt.Errorf("failed to send Open: %w", err)
+	}
+
+	if _, err := expectAMQPMethod(r, amqpConnection, amqpConnectionOpenOk); err != nil {
+		return fmt.Errorf("failed to open virtual host %q: %w", c.vhost, err)
+	}
+
+	return nil
+}
+
+// declareQueue opens a channel and declares the queue passively, which fails if it does not exist.
+func (c *AMQPChecker) declareQueue(conn net.Conn, r *bufio.Reader) error {
+	var e amqpEncoder
+	e.shortstr("") // reserved-1
+	if err := writeAMQPMethod(conn, 1, amqpChannel, amqpChannelOpen, e.buf); err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+	if _, err := expectAMQPMethod(r, amqpChannel, amqpChannelOpenOk); err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	e = amqpEncoder{}
+	e.short(0) // reserved-1
+	e.shortstr(c.queue)
+	e.octet(1) // passive
+	e.table(func(*amqpEncoder) {})
+	if err := writeAMQPMethod(conn, 1, amqpQueue, amqpQueueDeclare, e.buf); err != nil {
+		return fmt.Errorf("failed to declare queue: %w", err)
+	}
+
+	d, err := expectAMQPMethod(r, amqpQueue, amqpQueueDeclareOk)
+	if err != nil {
+		return fmt.Errorf("queue %q is not available: %w", c.queue, err)
+	}
+	d.shortstr() // queue
+	state := &amqpQueueState{messages: d.long(), consumers: d.long()}
+	if d.err != nil {
+		return d.err
+	}
+	c.lastQueue = state
+
+	return nil
+}
+
+// expectAMQPMethod reads the next method and returns a decoder for its arguments. Close methods sent
+// by the server are returned as errors.
+func expectAMQPMethod(r io.Reader, classID, methodID uint16) (*amqpDecoder, error) {
+	gotClass, gotMethod, d, err := readAMQPMethod(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case gotClass == classID && gotMethod == methodID:
+		return d, nil
+	case gotClass == amqpConnection && gotMethod == amqpConnectionClose:
+		code, text := d.short(), d.shortstr()
+		return nil, fmt.Errorf("connection closed by server with code %d: %s", code, text)
+	case gotClass == amqpChannel && gotMethod == amqpChannelClose:
+		code, text := d.short(), d.shortstr()
+		return nil, fmt.Errorf("channel closed by server with code %d: %s", code, text)
+	default:
+		return nil, fmt.Errorf("unexpected method %d.%d, expected %d.%d", gotClass, gotMethod, classID, methodID)
+	}
+}
+
+// readAMQPMethod reads frames until a method frame is received, skipping heartbeats.
+func readAMQPMethod(r io.Reader) (classID, methodID uint16, d *amqpDecoder, err error) {
+	for {
+		header := make([]byte, 7)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return 0, 0, nil, err
+		}
+
+		// Servers not supporting the protocol version reply with the header of a supported version
+		if string(header[:4]) == "AMQP" {
+			version := make([]byte, 1)
+			if _, err := io.ReadFull(r, version); err != nil {
+				return 0, 0, nil, err
+			}
+			return 0, 0, nil, fmt.Errorf("server does not support AMQP 0-9-1, it supports %d-%d-%d", header[5], header[6], version[0])
+		}
+
+		size := int(binary.BigEndian.Uint32(header[3:]))
+		if size > maxAMQPFrameSize {
+			return 0, 0, nil, fmt.Errorf("frame exceeds %d bytes", maxAMQPFrameSize)
+		}
+		payload := make([]byte, size+1)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return 0, 0, nil, err
+		}
+		if payload[size] != amqpFrameEnd {
+			return 0, 0, nil, errors.New("invalid frame end")
+		}
+
+		switch header[0] {
+		case amqpFrameHeartbeat:
+			continue
+		case amqpFrameMethod:
+			d := &amqpDecoder{data: payload[:size]}
+			classID, methodID := d.short(), d.short()
+			if d.err != nil {
+				return 0, 0, nil, d.err
+			}
+			return classID, methodID, d, nil
+		default:
+			return 0, 0, nil, fmt.Errorf("unexpected frame type %d", header[0])
+		}
+	}
+}
+
+// writeAMQPMethod writes a method frame.
+func writeAMQPMethod(w io.Writer, channel, classID, methodID uint16, args []byte) error {
+	frame := []byte{amqpFrameMethod}
+	frame = binary.BigEndian.AppendUint16(frame, channel)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(4+len(args)))
+	frame = binary.BigEndian.AppendUint16(frame, classID)
+	frame = binary.BigEndian.AppendUint16(frame, methodID)
+	frame = append(frame, args...)
+	frame = append(frame, amqpFrameEnd)
+
+	_, err := w.Write(frame)
+	return err
+}
+
+// amqpEncoder encodes the argument types of AMQP methods.
+type amqpEncoder struct {
+	buf []byte
+}
+
+func (e *amqpEncoder) octet(v byte)   { e.buf = append(e.buf, v) }
+func (e *amqpEncoder) short(v uint16) { e.buf = binary.BigEndian.AppendUint16(e.buf, v) }
+func (e *amqpEncoder) long(v uint32)  { e.buf = binary.BigEndian.AppendUint32(e.buf, v) }
+
+func (e *amqpEncoder) shortstr(v string) {
+	e.buf = append(e.buf, byte(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *amqpEncoder) longstr(v string) {
+	e.long(uint32(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+// table encodes a field table with the fields written by fields.
+func (e *amqpEncoder) table(fields func(*amqpEncoder)) {
+	var t amqpEncoder
+	fields(&t)
+	e.long(uint32(len(t.buf)))
+	e.buf = append(e.buf, t.buf...)
+}
+
+// errAMQPTruncated is returned for methods shorter than their arguments.
+var errAMQPTruncated = errors.New("invalid method: truncated")
+
+// amqpDecoder decodes the argument types of AMQP methods. The first error is kept and all
+// following reads return zero values.
+type amqpDecoder struct {
+	data []byte
+	err  error
+}
+
+// next returns the next n bytes, or nil if the data is too short.
+func (d *amqpDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > len(d.data) {
+		d.err = errAMQPTruncated
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *amqpDecoder) octet() byte {
+	if b := d.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (d *amqpDecoder) short() uint16 {
+	if b := d.next(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (d *amqpDecoder) long() uint32 {
+	if b := d.next(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (d *amqpDecoder) shortstr() string {
+	return string(d.next(int(d.octet())))
+}
+
+func (d *amqpDecoder) longstr() string {
+	return string(d.next(int(d.long())))
+}
+
+// table skips a field table.
+func (d *amqpDecoder) table() {
+	d.next(int(d.long()))
+}
+
+// newAMQPChecker creates a new AMQPChecker with functional options.
+func newAMQPChecker(name, address string, opts ...Option) (*AMQPChecker, error) {
+	checker := &AMQPChecker{
+		name:    name,
+		address: address,
+		timeout: defaultAMQPTimeout,
+		vhost:   defaultAMQPVHost,
+	}
+
+	for _, opt := range opts {
+		opt.apply(checker)
+	}
+
+	// RabbitMQ's default user, used when no credentials are configured
+	if checker.username == "" && checker.password == "" {
+		checker.username = defaultAMQPUsername
+		checker.password = defaultAMQPPassword
+	}
+
+	return checker, nil
+}
+
+// WithAMQPTimeout sets the timeout for connecting and the whole handshake.
+func WithAMQPTimeout(timeout time.Duration) Option {
+	return OptionFunc(func(c Checker) {
+		if amqpChecker, ok := c.(*AMQPChecker); ok {
+			amqpChecker.timeout = timeout
+		}
+	})
+}
+
+// WithAMQPCredentials sets the credentials used for PLAIN authentication.
+func WithAMQPCredentials(username, password string) Option {
+	return OptionFunc(func(c Checker) {
+		if amqpChecker, ok := c.(*AMQPChecker); ok {
+			amqpChecker.username = username
+			amqpChecker.password = password
+		}
+	})
+}
+
+// WithAMQPVHost sets the virtual host to open.
+func WithAMQPVHost(vhost string) Option {
+	return OptionFunc(func(c Checker) {
+		if amqpChecker, ok := c.(*AMQPChecker); ok {
+			amqpChecker.vhost = vhost
+		}
+	})
+}
+
+// WithAMQPQueue requires the queue to exist in the virtual host.
+func WithAMQPQueue(queue string) Option {
+	return OptionFunc(func(c Checker) {
+		if amqpChecker, ok := c.(*AMQPChecker); ok {
+			amqpChecker.queue = queue
+		}
+	})
+}
+
+// WithAMQPTLS connects with TLS using config.
+func WithAMQPTLS(config *tls.Config) Option {
+	return OptionFunc(func(c Checker) {
+		if amqpChecker, ok := c.(*AMQPChecker); ok {
+			amqpChecker.tlsConfig = config
+		}
+	})
+}
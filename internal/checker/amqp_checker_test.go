@@ -0,0 +1,265 @@
+package checker
+
+import (
+	"context"
+	"io"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAMQPBroker answers the AMQP 0-9-1 connection handshake and passive queue declarations.
+type fakeAMQPBroker struct {
+	username       string
+	password       string
+	vhosts         []string
+	queues         map[string]uint32 // Message counts by queue
+	protocolHeader []byte            // Sent instead of Connection.Start if set, like brokers not supporting 0-9-1
+}
+
+// startFakeAMQPBroker serves broker on a local listener and returns its address.
+func startFakeAMQPBroker(t *testing.T, broker fakeAMQPBroker) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go broker.serve(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func (b fakeAMQPBroker) serve(conn net.Conn) {
+	defer conn.Close()
+
+	header := make([]byte, len(amqpProtocolHeader))
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return
+	}
+	if b.protocolHeader != nil {
+		_, _ = conn.Write(b.protocolHeader)
+		return
+	}
+
+	var e amqpEncoder
+	e.octet(0)
+	e.octet(9)
+	e.table(func(props *amqpEncoder) {
+		props.shortstr("product")
+		props.octet('S')
+		props.longstr("RabbitMQ")
+	})
+	e.longstr("AMQPLAIN PLAIN")
+	e.longstr("en_US")
+	_ = writeAMQPMethod(conn, 0, amqpConnection, amqpConnectionStart, e.buf)
+
+	closeWith := func(channel, classID, methodID, code uint16, text string) {
+		var e amqpEncoder
+		e.short(code)
+		e.shortstr(text)
+		e.short(0)
+		e.short(0)
+		_ = writeAMQPMethod(conn, channel, classID, methodID, e.buf)
+	}
+
+	for {
+		classID, methodID, d, err := readAMQPMethod(conn)
+		if err != nil {
+			return
+		}
+
+		var e amqpEncoder
+		switch {
+		case classID == amqpConnection && methodID == amqpConnectionStartOk:
+			d.table()
+			d.shortstr()
+			if d.longstr() != "\x00"+b.username+"\x00"+b.password {
+				closeWith(0, amqpConnection, amqpConnectionClose, 403, "ACCESS_REFUSED - Login was refused using authentication mechanism PLAIN")
+				return
+			}
+			_, _ = conn.Write([]byte{amqpFrameHeartbeat, 0, 0, 0, 0, 0, 0, amqpFrameEnd})
+			e.short(2047)
+			e.long(131072)
+			e.short(60)
+			_ = writeAMQPMethod(conn, 0, amqpConnection, amqpConnectionTune, e.buf)
+		case classID == amqpConnection && methodID == amqpConnectionOpen:
+			vhost := d.shortstr()
+			if !slices.Contains(b.vhosts, vhost) {
+				closeWith(0, amqpConnection, amqpConnectionClose, 530, "NOT_ALLOWED - vhost "+vhost+" not found")
+				return
+			}
+			e.shortstr("")
+			_ = writeAMQPMethod(conn, 0, amqpConnection, amqpConnectionOpenOk, e.buf)
+		case classID == amqpChannel && methodID == amqpChannelOpen:
+			e.longstr("")
+			_ = writeAMQPMethod(conn, 1, amqpChannel, amqpChannelOpenOk, e.buf)
+		case classID == amqpQueue && methodID == amqpQueueDeclare:
+			d.short()
+			queue := d.shortstr()
+			messages, ok := b.queues[queue]
+			if !ok {
+				closeWith(1, amqpChannel, amqpChannelClose, 404, "NOT_FOUND - no queue '"+queue+"' in vhost '/'")
+				continue
+			}
+			e.shortstr(queue)
+			e.long(messages)
+			e.long(1)
+			_ = writeAMQPMethod(conn, 1, amqpQueue, amqpQueueDeclareOk, e.buf)
+		case classID == amqpConnection && methodID == amqpConnectionClose:
+			_ = writeAMQPMethod(conn, 0, amqpConnection, amqpConnectionCloseOk, nil)
+			return
+		}
+	}
+}
+
+func TestNewAMQPChecker(t *testing.T) {
+	t.Parallel()
+
+	checker, err := newAMQPChecker("example", "localhost:5672")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "example", checker.Name())
+	assert.Equal(t, "localhost:5672", checker.Address())
+	assert.Equal(t, AMQP.String(), checker.Type())
+	assert.Equal(t, "guest", checker.username)
+	assert.Equal(t, "guest", checker.password)
+	assert.Equal(t, "/", checker.vhost)
+
+	checker, err = newAMQPChecker("example", "localhost:5672",
+		WithAMQPTimeout(3*time.Second),
+		WithAMQPCredentials("app", "secret"),
+		WithAMQPVHost("orders"),
+		WithAMQPQueue("invoices"),
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3*time.Second, checker.timeout)
+	assert.Equal(t, "app", checker.username)
+	assert.Equal(t, "secret", checker.password)
+	assert.Equal(t, "orders", checker.vhost)
+	assert.Equal(t, "invoices", checker.queue)
+
+	checker, err = newAMQPChecker("example", "localhost:5672", WithAMQPCredentials("", ""))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "guest", checker.username)
+	assert.Equal(t, "guest", checker.password)
+}
+
+func TestAMQPChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	broker := fakeAMQPBroker{
+		username: "app",
+		password: "secret",
+		vhosts:   []string{"/", "orders"},
+		queues:   map[string]uint32{"invoices": 42},
+	}
+
+	t.Run("Handshake", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeAMQPBroker(t, broker)
+		checker, err := newAMQPChecker("rabbitmq", address, WithAMQPCredentials("app", "secret"))
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+		assert.Nil(t, checker.Details())
+	})
+
+	t.Run("Queue Exists", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeAMQPBroker(t, broker)
+		checker, err := newAMQPChecker("rabbitmq", address,
+			WithAMQPCredentials("app", "secret"),
+			WithAMQPVHost("orders"),
+			WithAMQPQueue("invoices"),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+		assert.Equal(t, int64(42), checker.Details()[0].Value.Int64())
+		assert.Equal(t, int64(1), checker.Details()[1].Value.Int64())
+	})
+
+	t.Run("Queue Missing", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeAMQPBroker(t, broker)
+		checker, err := newAMQPChecker("rabbitmq", address, WithAMQPCredentials("app", "secret"), WithAMQPQueue("missing"))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, `queue "missing" is not available: channel closed by server with code 404: NOT_FOUND - no queue 'missing' in vhost '/'`)
+	})
+
+	t.Run("Access Refused", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeAMQPBroker(t, broker)
+		checker, err := newAMQPChecker("rabbitmq", address)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "authentication failed: connection closed by server with code 403: ACCESS_REFUSED - Login was refused using authentication mechanism PLAIN")
+	})
+
+	t.Run("VHost Not Ready", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeAMQPBroker(t, broker)
+		checker, err := newAMQPChecker("rabbitmq", address, WithAMQPCredentials("app", "secret"), WithAMQPVHost("payments"))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, `failed to open virtual host "payments": connection closed by server with code 530: NOT_ALLOWED - vhost payments not found`)
+	})
+
+	t.Run("Unsupported Protocol Version", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeAMQPBroker(t, fakeAMQPBroker{protocolHeader: []byte("AMQP\x00\x01\x00\x00")})
+		checker, err := newAMQPChecker("rabbitmq", address)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "server does not support AMQP 0-9-1, it supports 1-0-0")
+	})
+
+	t.Run("No Reply", func(t *testing.T) {
+		t.Parallel()
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		t.Cleanup(func() { _ = ln.Close() })
+		go func() {
+			conn, err := ln.Accept()
+			if err == nil {
+				_, _ = io.Copy(io.Discard, conn) // Never replies, returns once the checker closes the connection
+				_ = conn.Close()
+			}
+		}()
+
+		checker, err := newAMQPChecker("rabbitmq", ln.Addr().String(), WithAMQPTimeout(100*time.Millisecond))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "i/o timeout")
+	})
+}
@@ -0,0 +1,245 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// BSON element types (https://bsonspec.org/spec.html) used by the MongoDB checker.
+const (
+	bsonDouble    byte = 0x01
+	bsonString    byte = 0x02
+	bsonDocument  byte = 0x03
+	bsonArray     byte = 0x04
+	bsonBinary    byte = 0x05
+	bsonUndefined byte = 0x06
+	bsonObjectID  byte = 0x07
+	bsonBool      byte = 0x08
+	bsonDateTime  byte = 0x09
+	bsonNull      byte = 0x0A
+	bsonInt32     byte = 0x10
+	bsonTimestamp byte = 0x11
+	bsonInt64     byte = 0x12
+	bsonDecimal   byte = 0x13
+)
+
+// bsonElement is a key/value pair of a bsonDoc. MongoDB commands must be encoded in order,
+// with the command name as the first key, so documents to encode are slices rather than maps.
+type bsonElement struct {
+	Key   string
+	Value any
+}
+
+// bsonDoc is an ordered BSON document for encoding.
+type bsonDoc []bsonElement
+
+// marshalBSON encodes doc. Supported values are string, int32, int, int64, float64, bool, []byte (generic
+// binary), bsonDoc and []any.
+func marshalBSON(doc bsonDoc) ([]byte, error) {
+	buf := []byte{0, 0, 0, 0} // Length, set at the end
+	for _, e := range doc {
+		var err error
+		if buf, err = appendBSONElement(buf, e.Key, e.Value); err != nil {
+			return nil, err
+		}
+	}
+	buf = append(buf, 0)
+	binary.LittleEndian.PutUint32(buf, uint32(len(buf)))
+	return buf, nil
+}
+
+// appendBSONElement appends a single element to buf.
+func appendBSONElement(buf []byte, key string, value any) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		buf = appendBSONKey(buf, bsonString, key)
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(v)+1))
+		buf = append(append(buf, v...), 0)
+	case int32:
+		buf = appendBSONKey(buf, bsonInt32, key)
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(v))
+	case int:
+		buf = appendBSONKey(buf, bsonInt64, key)
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(v))
+	case int64:
+		buf = appendBSONKey(buf, bsonInt64, key)
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(v))
+	case float64:
+		buf = appendBSONKey(buf, bsonDouble, key)
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+	case bool:
+		buf = appendBSONKey(buf, bsonBool, key)
+		if v {
+			buf = append(buf, 1)
+		} else {
+			buf = append(buf, 0)
+		}
+	case []byte:
+		buf = appendBSONKey(buf, bsonBinary, key)
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(v)))
+		buf = append(append(buf, 0), v...) // Generic binary subtype
+	case bsonDoc:
+		encoded, err := marshalBSON(v)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(appendBSONKey(buf, bsonDocument, key), encoded...)
+	case []any:
+		array := make(bsonDoc, len(v))
+		for i, item := range v {
+			array[i] = bsonElement{Key: strconv.Itoa(i), Value: item}
+		}
+		encoded, err := marshalBSON(array)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(appendBSONKey(buf, bsonArray, key), encoded...)
+	default:
+		return nil, fmt.Errorf("unsupported BSON value for %q: %T", key, value)
+	}
+	return buf, nil
+}
+
+// appendBSONKey appends the element type and the null-terminated key.
+func appendBSONKey(buf []byte, kind byte, key string) []byte {
+	return append(append(append(buf, kind), key...), 0)
+}
+
+// unmarshalBSON decodes a document into a map. Embedded documents become maps and arrays become []any;
+// strings, numbers, booleans, binary data and null are decoded, other types are skipped as nil.
+func unmarshalBSON(data []byte) (map[string]any, error) {
+	doc, n, err := decodeBSONDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(data) {
+		return nil, fmt.Errorf("invalid BSON document: %d trailing bytes", len(data)-n)
+	}
+	return doc, nil
+}
+
+// errBSONTruncated is returned for documents shorter than their encoded length.
+var errBSONTruncated = errors.New("invalid BSON document: truncated")
+
+// decodeBSONDocument decodes the document at the start of data and returns its encoded length.
+func decodeBSONDocument(data []byte) (map[string]any, int, error) {
+	if len(data) < 5 {
+		return nil, 0, errBSONTruncated
+	}
+	length := int(binary.LittleEndian.Uint32(data))
+	if length < 5 || length > len(data) {
+		return nil, 0, errBSONTruncated
+	}
+	if data[length-1] != 0 {
+		return nil, 0, errors.New("invalid BSON document: missing terminator")
+	}
+
+	doc := make(map[string]any)
+	body := data[4 : length-1]
+	for len(body) > 0 {
+		kind := body[0]
+		end := bytes.IndexByte(body[1:], 0)
+		if end < 0 {
+			return nil, 0, errBSONTruncated
+		}
+		key := string(body[1 : end+1])
+
+		value, n, err := decodeBSONValue(kind, body[end+2:])
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid BSON element %q: %w", key, err)
+		}
+		doc[key] = value
+		body = body[end+2+n:]
+	}
+
+	return doc, length, nil
+}
+
+// decodeBSONValue decodes a value of the given type at the start of data and returns its encoded length.
+func decodeBSONValue(kind byte, data []byte) (any, int, error) {
+	fixed := func(size int) error {
+		if len(data) < size {
+			return errBSONTruncated
+		}
+		return nil
+	}
+
+	switch kind {
+	case bsonDouble:
+		if err := fixed(8); err != nil {
+			return nil, 0, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(data)), 8, nil
+	case bsonString:
+		if err := fixed(4); err != nil {
+			return nil, 0, err
+		}
+		size := int(binary.LittleEndian.Uint32(data))
+		if size < 1 || 4+size > len(data) {
+			return nil, 0, errBSONTruncated
+		}
+		return string(data[4 : 4+size-1]), 4 + size, nil
+	case bsonDocument:
+		return decodeBSONDocument(data)
+	case bsonArray:
+		doc, n, err := decodeBSONDocument(data)
+		if err != nil {
+			return nil, 0, err
+		}
+		array := make([]any, len(doc))
+		for i := range array {
+			array[i] = doc[strconv.Itoa(i)]
+		}
+		return array, n, nil
+	case bsonBinary:
+		if err := fixed(5); err != nil {
+			return nil, 0, err
+		}
+		size := int(binary.LittleEndian.Uint32(data))
+		if size < 0 || 5+size > len(data) {
+			return nil, 0, errBSONTruncated
+		}
+		return bytes.Clone(data[5 : 5+size]), 5 + size, nil
+	case bsonUndefined, bsonNull:
+		return nil, 0, nil
+	case bsonObjectID:
+		return nil, 12, fixed(12)
+	case bsonBool:
+		if err := fixed(1); err != nil {
+			return nil, 0, err
+		}
+		return data[0] != 0, 1, nil
+	case bsonInt32:
+		if err := fixed(4); err != nil {
+			return nil, 0, err
+		}
+		return int32(binary.LittleEndian.Uint32(data)), 4, nil
+	case bsonDateTime, bsonTimestamp, bsonInt64:
+		if err := fixed(8); err != nil {
+			return nil, 0, err
+		}
+		return int64(binary.LittleEndian.Uint64(data)), 8, nil
+	case bsonDecimal:
+		return nil, 16, fixed(16)
+	default:
+		return nil, 0, fmt.Errorf("unsupported type %#x", kind)
+	}
+}
+
+// bsonNumber converts a decoded BSON number to float64.
+func bsonNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
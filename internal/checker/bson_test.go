@@ -0,0 +1,94 @@
+package checker
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalBSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Round Trip", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := marshalBSON(bsonDoc{
+			{"hello", int32(1)},
+			{"count", 42},
+			{"ratio", 0.5},
+			{"name", "rs0"},
+			{"secondary", true},
+			{"payload", []byte("n,,n=user")},
+			{"options", bsonDoc{{"skipEmptyExchange", true}}},
+			{"hosts", []any{"a:27017", "b:27017"}},
+		})
+		assert.NoError(t, err)
+
+		decoded, err := unmarshalBSON(encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"hello":     int32(1),
+			"count":     int64(42),
+			"ratio":     0.5,
+			"name":      "rs0",
+			"secondary": true,
+			"payload":   []byte("n,,n=user"),
+			"options":   map[string]any{"skipEmptyExchange": true},
+			"hosts":     []any{"a:27017", "b:27017"},
+		}, decoded)
+	})
+
+	t.Run("Spec Example", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := marshalBSON(bsonDoc{{"hello", "world"}})
+		assert.NoError(t, err)
+		assert.Equal(t, "160000000268656c6c6f0006000000776f726c640000", hex.EncodeToString(encoded))
+	})
+
+	t.Run("Unsupported Value", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := marshalBSON(bsonDoc{{"value", uint8(1)}})
+		assert.EqualError(t, err, `unsupported BSON value for "value": uint8`)
+	})
+}
+
+func TestUnmarshalBSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Skipped Types", func(t *testing.T) {
+		t.Parallel()
+
+		// {"_id": ObjectId, "null": null, "when": DateTime(1)}
+		data, _ := hex.DecodeString("2a000000075f6964000102030405060708090a0b0c0a6e756c6c00097768656e00010000000000000000")
+		decoded, err := unmarshalBSON(data)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"_id": nil, "null": nil, "when": int64(1)}, decoded)
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, _ := marshalBSON(bsonDoc{{"hello", "world"}})
+		_, err := unmarshalBSON(encoded[:len(encoded)-3])
+		assert.EqualError(t, err, "invalid BSON document: truncated")
+	})
+
+	t.Run("Invalid String Length", func(t *testing.T) {
+		t.Parallel()
+
+		data, _ := hex.DecodeString("160000000268656c6c6f00ff000000776f726c640000")
+		_, err := unmarshalBSON(data)
+		assert.EqualError(t, err, `invalid BSON element "hello": invalid BSON document: truncated`)
+	})
+
+	t.Run("Unsupported Type", func(t *testing.T) {
+		t.Parallel()
+
+		data, _ := hex.DecodeString("0d0000007f6d61786b65790000")
+		_, err := unmarshalBSON(data)
+		assert.EqualError(t, err, `invalid BSON element "maxkey": unsupported type 0x7f`)
+	})
+}
@@ -3,6 +3,7 @@ package checker
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 )
 
@@ -10,9 +11,20 @@ import (
 type CheckType string
 
 const (
-	TCP  CheckType = "TCP" // TCP represents a check over the TCP protocol.
-	HTTP CheckType = "HTTP"
-	ICMP CheckType = "ICMP"
+	TCP       CheckType = "TCP" // TCP represents a check over the TCP protocol.
+	HTTP      CheckType = "HTTP"
+	ICMP      CheckType = "ICMP"
+	TLS       CheckType = "TLS"
+	UDP       CheckType = "UDP"
+	Unix      CheckType = "UNIX"
+	File      CheckType = "FILE"
+	Exec      CheckType = "EXEC"
+	WebSocket CheckType = "WEBSOCKET"
+	MongoDB   CheckType = "MONGODB"
+	Kafka     CheckType = "KAFKA"
+	AMQP      CheckType = "AMQP"
+	MQTT      CheckType = "MQTT"
+	NATS      CheckType = "NATS"
 )
 
 // String returns the string representation of the CheckType.
@@ -42,6 +54,12 @@ type Checker interface {
 	Address() string                 // Address returns the address of the checker.
 }
 
+// Detailer is implemented by checkers that provide details about their most recent check, such as the
+// results for each resolved address. The details are added to the log entry of each attempt.
+type Detailer interface {
+	Details() []slog.Attr // Details returns the details of the most recent check.
+}
+
 // ParseCheckType converts a string to a CheckType enum.
 func ParseCheckType(typeStr string) (CheckType, error) {
 	switch strings.ToLower(typeStr) {
@@ -51,6 +69,28 @@ func ParseCheckType(typeStr string) (CheckType, error) {
 		return TCP, nil
 	case "icmp":
 		return ICMP, nil
+	case "tls":
+		return TLS, nil
+	case "udp":
+		return UDP, nil
+	case "unix":
+		return Unix, nil
+	case "file":
+		return File, nil
+	case "exec":
+		return Exec, nil
+	case "websocket":
+		return WebSocket, nil
+	case "mongodb":
+		return MongoDB, nil
+	case "kafka":
+		return Kafka, nil
+	case "amqp":
+		return AMQP, nil
+	case "mqtt":
+		return MQTT, nil
+	case "nats":
+		return NATS, nil
 	default:
 		return "", fmt.Errorf("unsupported check type: %s", typeStr)
 	}
@@ -66,6 +106,28 @@ func NewChecker(checkType CheckType, name, address string, opts ...Option) (Chec
 		return newTCPChecker(name, address, opts...)
 	case ICMP:
 		return newICMPChecker(name, address, opts...)
+	case TLS:
+		return newTLSChecker(name, address, opts...)
+	case UDP:
+		return newUDPChecker(name, address, opts...)
+	case Unix:
+		return newUnixChecker(name, address, opts...)
+	case File:
+		return newFileChecker(name, address, opts...)
+	case Exec:
+		return newExecChecker(name, address, opts...)
+	case WebSocket:
+		return newWebSocketChecker(name, address, opts...)
+	case MongoDB:
+		return newMongoDBChecker(name, address, opts...)
+	case Kafka:
+		return newKafkaChecker(name, address, opts...)
+	case AMQP:
+		return newAMQPChecker(name, address, opts...)
+	case MQTT:
+		return newMQTTChecker(name, address, opts...)
+	case NATS:
+		return newNATSChecker(name, address, opts...)
 	default:
 		return nil, fmt.Errorf("unsupported check type: %s", checkType)
 	}
@@ -39,6 +39,116 @@ func TestNewChecker(t *testing.T) {
 		assert.Equal(t, check.Type(), "ICMP")
 	})
 
+	t.Run("Valid TLS checker", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := NewChecker(TLS, "example", "example.com:443")
+
+		assert.NoError(t, err)
+		assert.Equal(t, check.Name(), "example")
+		assert.Equal(t, check.Type(), "TLS")
+	})
+
+	t.Run("Valid UDP checker", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := NewChecker(UDP, "example", "example.com:53")
+
+		assert.NoError(t, err)
+		assert.Equal(t, check.Name(), "example")
+		assert.Equal(t, check.Type(), "UDP")
+	})
+
+	t.Run("Valid Unix checker", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := NewChecker(Unix, "example", "/var/run/app.sock")
+
+		assert.NoError(t, err)
+		assert.Equal(t, check.Name(), "example")
+		assert.Equal(t, check.Type(), "UNIX")
+	})
+
+	t.Run("Valid File checker", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := NewChecker(File, "example", "/vault/secrets/config")
+
+		assert.NoError(t, err)
+		assert.Equal(t, check.Name(), "example")
+		assert.Equal(t, check.Type(), "FILE")
+	})
+
+	t.Run("Valid Exec checker", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := NewChecker(Exec, "example", "pg_isready")
+
+		assert.NoError(t, err)
+		assert.Equal(t, check.Name(), "example")
+		assert.Equal(t, check.Type(), "EXEC")
+	})
+
+	t.Run("Valid WebSocket checker", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := NewChecker(WebSocket, "example", "wss://example.com/ws")
+
+		assert.NoError(t, err)
+		assert.Equal(t, check.Name(), "example")
+		assert.Equal(t, check.Type(), "WEBSOCKET")
+	})
+
+	t.Run("Valid MongoDB checker", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := NewChecker(MongoDB, "example", "localhost:27017")
+
+		assert.NoError(t, err)
+		assert.Equal(t, check.Name(), "example")
+		assert.Equal(t, check.Type(), "MONGODB")
+	})
+
+	t.Run("Valid Kafka checker", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := NewChecker(Kafka, "example", "localhost:9092")
+
+		assert.NoError(t, err)
+		assert.Equal(t, check.Name(), "example")
+		assert.Equal(t, check.Type(), "KAFKA")
+	})
+
+	t.Run("Valid AMQP checker", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := NewChecker(AMQP, "example", "localhost:5672")
+
+		assert.NoError(t, err)
+		assert.Equal(t, check.Name(), "example")
+		assert.Equal(t, check.Type(), "AMQP")
+	})
+
+	t.Run("Valid MQTT checker", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := NewChecker(MQTT, "example", "mqtt://localhost:1883")
+
+		assert.NoError(t, err)
+		assert.Equal(t, check.Name(), "example")
+		assert.Equal(t, check.Type(), "MQTT")
+	})
+
+	t.Run("Valid NATS checker", func(t *testing.T) {
+		t.Parallel()
+
+		check, err := NewChecker(NATS, "example", "localhost:4222")
+
+		assert.NoError(t, err)
+		assert.Equal(t, check.Name(), "example")
+		assert.Equal(t, check.Type(), "NATS")
+	})
+
 	t.Run("Invalid checker type", func(t *testing.T) {
 		t.Parallel()
 
@@ -106,6 +216,105 @@ func TestParseCheckType(t *testing.T) {
 		assert.Equal(t, result, ICMP)
 	})
 
+	t.Run("Check type tls", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseCheckType("tls")
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, TLS)
+	})
+
+	t.Run("Check type udp", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseCheckType("udp")
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, UDP)
+	})
+
+	t.Run("Check type unix", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseCheckType("unix")
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, Unix)
+	})
+
+	t.Run("Check type file", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseCheckType("file")
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, File)
+	})
+
+	t.Run("Check type exec", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseCheckType("exec")
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, Exec)
+	})
+
+	t.Run("Check type websocket", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseCheckType("websocket")
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, WebSocket)
+	})
+
+	t.Run("Check type mongodb", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseCheckType("mongodb")
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, MongoDB)
+	})
+
+	t.Run("Check type kafka", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseCheckType("kafka")
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, Kafka)
+	})
+
+	t.Run("Check type amqp", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseCheckType("amqp")
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, AMQP)
+	})
+
+	t.Run("Check type mqtt", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseCheckType("mqtt")
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, MQTT)
+	})
+
+	t.Run("Check type nats", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseCheckType("nats")
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, NATS)
+	})
+
 	t.Run("Invalid check type", func(t *testing.T) {
 		t.Parallel()
 
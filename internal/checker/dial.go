@@ -0,0 +1,33 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+)
+
+// dialTCP connects to the "host:port" address, using TLS if config is set. The server name defaults to the
+// host of the address.
+func dialTCP(ctx context.Context, address string, config *tls.Config) (net.Conn, error) {
+	if config == nil {
+		return (&net.Dialer{}).DialContext(ctx, "tcp", address)
+	}
+
+	conn, err := (&tls.Dialer{Config: clientTLSConfig(address, config)}).DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	return conn, nil
+}
+
+// clientTLSConfig returns a copy of config with the server name defaulting to the host of the "host:port" address.
+func clientTLSConfig(address string, config *tls.Config) *tls.Config {
+	config = config.Clone()
+	if config.ServerName == "" {
+		if host, _, err := net.SplitHostPort(address); err == nil {
+			config.ServerName = host
+		}
+	}
+	return config
+}
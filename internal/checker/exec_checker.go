@@ -0,0 +1,191 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	defaultExecTimeout   time.Duration = 5 * time.Second
+	defaultExecWaitDelay time.Duration = 1 * time.Second // Time to wait for the output pipes after the process was killed
+	maxExecOutputSize    int           = 64 * 1024       // Bytes of output kept per run
+)
+
+var defaultExecExitCodes = []int{0}
+
+// ExecChecker implements the Checker interface by running a command, e.g. pg_isready.
+type ExecChecker struct {
+	name         string
+	address      string          // Command to run
+	args         []string        // Arguments of the command
+	env          []string        // Variables in "KEY=VALUE" format added to the environment of the process
+	timeout      time.Duration   // Timeout after which the process is killed
+	exitCodes    []int           // Exit codes treated as ready
+	response     responseMatcher // Expected stdout, not checked if not configured
+	lastExitCode *int
+}
+
+func (c *ExecChecker) Address() string { return c.address }
+func (c *ExecChecker) Name() string    { return c.name }
+func (c *ExecChecker) Type() string    { return Exec.String() }
+
+// Details returns the exit code of the command run in the last check.
+func (c *ExecChecker) Details() []slog.Attr {
+	if c.lastExitCode == nil {
+		return nil
+	}
+	return []slog.Attr{slog.Int("exit_code", *c.lastExitCode)}
+}
+
+func (c *ExecChecker) Check(ctx context.Context) error {
+	c.lastExitCode = nil
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// The process is killed once ctx is done
+	cmd := exec.CommandContext(ctx, c.address, c.args...)
+	cmd.Env = append(os.Environ(), c.env...)
+	cmd.WaitDelay = defaultExecWaitDelay
+
+	output := &outputBuffer{}
+	stdout := &outputBuffer{}
+	cmd.Stdout = &teeWriter{stdout, output}
+	cmd.Stderr = output
+
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return fmt.Errorf("command did not finish: %w: output %s", ctx.Err(), quoteBytes(output.Bytes()))
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return fmt.Errorf("failed to run command: %w", err)
+	}
+
+	exitCode := cmd.ProcessState.ExitCode()
+	c.lastExitCode = &exitCode
+
+	if !slices.Contains(c.exitCodes, exitCode) {
+		return fmt.Errorf("unexpected exit code: got %d, expected one of %v: output %s", exitCode, c.exitCodes, quoteBytes(output.Bytes()))
+	}
+
+	if c.response.configured() && !c.response.matches(stdout.Bytes()) {
+		return c.response.mismatch(stdout.Bytes(), nil)
+	}
+
+	return nil
+}
+
+// outputBuffer collects up to maxExecOutputSize bytes of output written concurrently by stdout and stderr.
+type outputBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *outputBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if remaining := maxExecOutputSize - b.buf.Len(); remaining > 0 {
+		b.buf.Write(p[:min(len(p), remaining)])
+	}
+	return len(p), nil // Excess output is discarded rather than failing the command
+}
+
+// Bytes returns the collected output.
+func (b *outputBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return bytes.Clone(b.buf.Bytes())
+}
+
+// teeWriter writes to both buffers, keeping stdout separately from the combined output.
+type teeWriter struct {
+	stdout, output *outputBuffer
+}
+
+func (w *teeWriter) Write(p []byte) (int, error) {
+	_, _ = w.stdout.Write(p)
+	return w.output.Write(p)
+}
+
+// newExecChecker creates a new ExecChecker with functional options.
+func newExecChecker(name, address string, opts ...Option) (*ExecChecker, error) {
+	checker := &ExecChecker{
+		name:      name,
+		address:   address,
+		timeout:   defaultExecTimeout,
+		exitCodes: defaultExecExitCodes,
+	}
+
+	for _, opt := range opts {
+		opt.apply(checker)
+	}
+
+	return checker, nil
+}
+
+// WithExecArgs sets the arguments of the command.
+func WithExecArgs(args []string) Option {
+	return OptionFunc(func(c Checker) {
+		if execChecker, ok := c.(*ExecChecker); ok {
+			execChecker.args = args
+		}
+	})
+}
+
+// WithExecEnv adds variables in "KEY=VALUE" format to the environment of the command.
+func WithExecEnv(env []string) Option {
+	return OptionFunc(func(c Checker) {
+		if execChecker, ok := c.(*ExecChecker); ok {
+			execChecker.env = env
+		}
+	})
+}
+
+// WithExecTimeout sets the timeout after which the command is killed.
+func WithExecTimeout(timeout time.Duration) Option {
+	return OptionFunc(func(c Checker) {
+		if execChecker, ok := c.(*ExecChecker); ok {
+			execChecker.timeout = timeout
+		}
+	})
+}
+
+// WithExecExitCodes sets the exit codes treated as ready.
+func WithExecExitCodes(codes []int) Option {
+	return OptionFunc(func(c Checker) {
+		if execChecker, ok := c.(*ExecChecker); ok {
+			execChecker.exitCodes = codes
+		}
+	})
+}
+
+// WithExecExpect requires the stdout of the command to contain data.
+func WithExecExpect(data []byte) Option {
+	return OptionFunc(func(c Checker) {
+		if execChecker, ok := c.(*ExecChecker); ok {
+			execChecker.response.expect = data
+		}
+	})
+}
+
+// WithExecExpectPattern requires the stdout of the command to match re.
+func WithExecExpectPattern(re *regexp.Regexp) Option {
+	return OptionFunc(func(c Checker) {
+		if execChecker, ok := c.(*ExecChecker); ok {
+			execChecker.response.expectRegex = re
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package checker
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewExecChecker(t *testing.T) {
+	t.Parallel()
+
+	checker, err := newExecChecker("example", "pg_isready",
+		WithExecArgs([]string{"-h", "localhost"}),
+		WithExecTimeout(2*time.Second),
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "example", checker.Name())
+	assert.Equal(t, "pg_isready", checker.Address())
+	assert.Equal(t, Exec.String(), checker.Type())
+	assert.Equal(t, []string{"-h", "localhost"}, checker.args)
+	assert.Equal(t, 2*time.Second, checker.timeout)
+	assert.Equal(t, []int{0}, checker.exitCodes)
+}
+
+func TestExecChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Exit Code Zero", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newExecChecker("true", "sh", WithExecArgs([]string{"-c", "exit 0"}))
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+		assert.Equal(t, "exit_code", checker.Details()[0].Key)
+		assert.Equal(t, int64(0), checker.Details()[0].Value.Int64())
+	})
+
+	t.Run("Unexpected Exit Code", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newExecChecker("fail", "sh", WithExecArgs([]string{"-c", "echo 'no response' >&2; exit 2"}))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, `unexpected exit code: got 2, expected one of [0]: output "no response\n"`)
+		assert.Equal(t, int64(2), checker.Details()[0].Value.Int64())
+	})
+
+	t.Run("Configured Exit Codes", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newExecChecker("fail", "sh",
+			WithExecArgs([]string{"-c", "exit 3"}),
+			WithExecExitCodes([]int{0, 3}),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Env", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newExecChecker("env", "sh",
+			WithExecArgs([]string{"-c", `test "$PGHOST" = db`}),
+			WithExecEnv([]string{"PGHOST=db"}),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Stdout Matches", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newExecChecker("mongosh", "sh",
+			WithExecArgs([]string{"-c", `echo '{ "ok": 1 }'; echo warning >&2`}),
+			WithExecExpectPattern(regexp.MustCompile(`"ok": 1`)),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Stdout Mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newExecChecker("mongosh", "sh",
+			WithExecArgs([]string{"-c", `echo '{ "ok": 0 }'`}),
+			WithExecExpect([]byte(`"ok": 1`)),
+		)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, `unexpected response, expected "\"ok\": 1": received 12 bytes "{ \"ok\": 0 }\n"`)
+	})
+
+	t.Run("Output Is Truncated", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newExecChecker("verbose", "sh", WithExecArgs([]string{"-c", "yes | head -c 100000; exit 1"}))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.True(t, strings.HasSuffix(err.Error(), `"...`))
+		assert.Less(t, len(err.Error()), 2*maxReportedBytes)
+	})
+
+	t.Run("Command Not Found", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newExecChecker("missing", "portpatrol-missing-command")
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, `failed to run command: exec: "portpatrol-missing-command": executable file not found in $PATH`)
+		assert.Nil(t, checker.Details())
+	})
+
+	t.Run("Timeout Kills Process", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newExecChecker("slow", "sh",
+			WithExecArgs([]string{"-c", "echo waiting; sleep 10"}),
+			WithExecTimeout(100*time.Millisecond),
+		)
+		assert.NoError(t, err)
+
+		start := time.Now()
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, `command did not finish: context deadline exceeded: output "waiting\n"`)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("Context Cancel Kills Process", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newExecChecker("slow", "sleep", WithExecArgs([]string{"10"}))
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		start := time.Now()
+		err = checker.Check(ctx)
+		assert.EqualError(t, err, `command did not finish: context canceled: output ""`)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+}
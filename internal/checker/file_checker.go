@@ -0,0 +1,221 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileChecker implements the Checker interface for files and directories written asynchronously,
+// e.g. secrets or certificates rendered into a shared volume by another container.
+type FileChecker struct {
+	name          string
+	address       string
+	nonEmpty      bool           // Require a non-empty file or a directory with entries
+	contentRegex  *regexp.Regexp // Pattern the content must match
+	jsonPath      string         // Dot separated path that must exist in the JSON content
+	jsonValue     *string        // Value expected at jsonPath, nil accepts any value
+	permissions   *fs.FileMode   // Exact permission bits the file must have
+	modifiedAfter time.Time      // Time the file must have been modified after
+	lastInfo      fs.FileInfo
+}
+
+func (c *FileChecker) Address() string { return c.address }
+func (c *FileChecker) Name() string    { return c.name }
+func (c *FileChecker) Type() string    { return File.String() }
+
+// Details returns the size and modification time of the file found in the last check.
+func (c *FileChecker) Details() []slog.Attr {
+	if c.lastInfo == nil {
+		return nil
+	}
+	return []slog.Attr{
+		slog.Int64("size", c.lastInfo.Size()),
+		slog.String("modified", c.lastInfo.ModTime().UTC().Format(time.RFC3339)),
+	}
+}
+
+func (c *FileChecker) Check(ctx context.Context) error {
+	c.lastInfo = nil
+
+	info, err := os.Stat(c.address)
+	if err != nil {
+		return err
+	}
+	c.lastInfo = info
+
+	if c.permissions != nil && info.Mode().Perm() != *c.permissions {
+		return fmt.Errorf("unexpected permissions: got %04o, expected %04o", info.Mode().Perm(), *c.permissions)
+	}
+
+	if !c.modifiedAfter.IsZero() && !info.ModTime().After(c.modifiedAfter) {
+		return fmt.Errorf("not modified since %s, last modified at %s",
+			c.modifiedAfter.UTC().Format(time.RFC3339), info.ModTime().UTC().Format(time.RFC3339))
+	}
+
+	if c.nonEmpty {
+		if err := checkNotEmpty(c.address, info); err != nil {
+			return err
+		}
+	}
+
+	if c.contentRegex == nil && c.jsonPath == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(c.address)
+	if err != nil {
+		return err
+	}
+
+	if c.contentRegex != nil && !c.contentRegex.Match(content) {
+		return fmt.Errorf("content does not match pattern %q", c.contentRegex.String())
+	}
+
+	if c.jsonPath != "" {
+		return c.checkJSON(content)
+	}
+
+	return nil
+}
+
+// checkNotEmpty fails for empty files and directories without entries.
+func checkNotEmpty(path string, info fs.FileInfo) error {
+	if !info.IsDir() {
+		if info.Size() == 0 {
+			return errors.New("file is empty")
+		}
+		return nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return errors.New("directory is empty")
+	}
+	return nil
+}
+
+// checkJSON verifies that the JSON path exists in content and, if configured, holds the expected value.
+func (c *FileChecker) checkJSON(content []byte) error {
+	var data any
+	if err := json.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	value, err := lookupJSONPath(data, c.jsonPath)
+	if err != nil {
+		return fmt.Errorf("JSON path %q not found: %w", c.jsonPath, err)
+	}
+
+	if c.jsonValue == nil {
+		return nil
+	}
+
+	// Strings are compared as is, other values by their JSON representation
+	got, ok := value.(string)
+	if !ok {
+		encoded, _ := json.Marshal(value)
+		got = string(encoded)
+	}
+	if got != *c.jsonValue {
+		return fmt.Errorf("unexpected value at JSON path %q: got %q, expected %q", c.jsonPath, got, *c.jsonValue)
+	}
+
+	return nil
+}
+
+// lookupJSONPath walks a dot separated path like "servers.0.host" through decoded JSON objects and arrays.
+func lookupJSONPath(data any, path string) (any, error) {
+	current := data
+	for _, key := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[key]
+			if !ok {
+				return nil, fmt.Errorf("key %q not found", key)
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("invalid array index %q", key)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("key %q not found", key)
+		}
+	}
+	return current, nil
+}
+
+// newFileChecker creates a new FileChecker with functional options.
+func newFileChecker(name, address string, opts ...Option) (*FileChecker, error) {
+	checker := &FileChecker{
+		name:    name,
+		address: address,
+	}
+
+	for _, opt := range opts {
+		opt.apply(checker)
+	}
+
+	return checker, nil
+}
+
+// WithFileNonEmpty requires the file to have content, or the directory to have entries.
+func WithFileNonEmpty(nonEmpty bool) Option {
+	return OptionFunc(func(c Checker) {
+		if fileChecker, ok := c.(*FileChecker); ok {
+			fileChecker.nonEmpty = nonEmpty
+		}
+	})
+}
+
+// WithFileContentPattern requires the content of the file to match re.
+func WithFileContentPattern(re *regexp.Regexp) Option {
+	return OptionFunc(func(c Checker) {
+		if fileChecker, ok := c.(*FileChecker); ok {
+			fileChecker.contentRegex = re
+		}
+	})
+}
+
+// WithFileJSONPath requires the file to contain JSON with the dot separated path. If value is not nil,
+// the path must hold it; strings are compared as is, other values by their JSON representation.
+func WithFileJSONPath(path string, value *string) Option {
+	return OptionFunc(func(c Checker) {
+		if fileChecker, ok := c.(*FileChecker); ok {
+			fileChecker.jsonPath = path
+			fileChecker.jsonValue = value
+		}
+	})
+}
+
+// WithFilePermissions requires the permission bits of the file to equal perm.
+func WithFilePermissions(perm fs.FileMode) Option {
+	return OptionFunc(func(c Checker) {
+		if fileChecker, ok := c.(*FileChecker); ok {
+			fileChecker.permissions = &perm
+		}
+	})
+}
+
+// WithFileModifiedAfter requires the file to have been modified after t, e.g. the start of the process.
+func WithFileModifiedAfter(t time.Time) Option {
+	return OptionFunc(func(c Checker) {
+		if fileChecker, ok := c.(*FileChecker); ok {
+			fileChecker.modifiedAfter = t
+		}
+	})
+}
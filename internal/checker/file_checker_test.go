@@ -0,0 +1,194 @@
+package checker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeFile creates a file with content and permissions in a temporary directory.
+func writeFile(t *testing.T, content string, perm os.FileMode) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatalf("failed to write file: %q", err)
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		t.Fatalf("failed to change permissions: %q", err)
+	}
+	return path
+}
+
+func TestNewFileChecker(t *testing.T) {
+	t.Parallel()
+
+	checker, err := newFileChecker("example", "/vault/secrets/config", WithFileNonEmpty(true))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "example", checker.Name())
+	assert.Equal(t, "/vault/secrets/config", checker.Address())
+	assert.Equal(t, File.String(), checker.Type())
+	assert.True(t, checker.nonEmpty)
+}
+
+func TestFileChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	t.Run("File Exists", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeFile(t, "", 0o600)
+		checker, err := newFileChecker("secret", path)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+
+		details := checker.Details()
+		assert.Len(t, details, 2)
+		assert.Equal(t, "size", details[0].Key)
+		assert.Equal(t, int64(0), details[0].Value.Int64())
+	})
+
+	t.Run("File Missing", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "missing")
+		checker, err := newFileChecker("secret", path)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "stat "+path+": no such file or directory")
+		assert.Nil(t, checker.Details())
+	})
+
+	t.Run("Non Empty", func(t *testing.T) {
+		t.Parallel()
+
+		empty := writeFile(t, "", 0o600)
+		checker, err := newFileChecker("secret", empty, WithFileNonEmpty(true))
+		assert.NoError(t, err)
+		assert.EqualError(t, checker.Check(context.Background()), "file is empty")
+
+		filled := writeFile(t, "token", 0o600)
+		checker, err = newFileChecker("secret", filled, WithFileNonEmpty(true))
+		assert.NoError(t, err)
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Non Empty Directory", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		checker, err := newFileChecker("certs", dir, WithFileNonEmpty(true))
+		assert.NoError(t, err)
+		assert.EqualError(t, checker.Check(context.Background()), "directory is empty")
+
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "tls.crt"), []byte("cert"), 0o600))
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Content Pattern", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeFile(t, "-----BEGIN CERTIFICATE-----\n...", 0o600)
+		checker, err := newFileChecker("cert", path, WithFileContentPattern(regexp.MustCompile(`BEGIN CERTIFICATE`)))
+		assert.NoError(t, err)
+		assert.NoError(t, checker.Check(context.Background()))
+
+		checker, err = newFileChecker("cert", path, WithFileContentPattern(regexp.MustCompile(`BEGIN PRIVATE KEY`)))
+		assert.NoError(t, err)
+		assert.EqualError(t, checker.Check(context.Background()), `content does not match pattern "BEGIN PRIVATE KEY"`)
+	})
+
+	t.Run("JSON Path", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeFile(t, `{"status":{"ready":true,"nodes":[{"name":"vault-0"}]}}`, 0o600)
+
+		checker, err := newFileChecker("status", path, WithFileJSONPath("status.nodes.0.name", nil))
+		assert.NoError(t, err)
+		assert.NoError(t, checker.Check(context.Background()))
+
+		expected := "true"
+		checker, err = newFileChecker("status", path, WithFileJSONPath("status.ready", &expected))
+		assert.NoError(t, err)
+		assert.NoError(t, checker.Check(context.Background()))
+
+		expected = "vault-1"
+		checker, err = newFileChecker("status", path, WithFileJSONPath("status.nodes.0.name", &expected))
+		assert.NoError(t, err)
+		assert.EqualError(t, checker.Check(context.Background()),
+			`unexpected value at JSON path "status.nodes.0.name": got "vault-0", expected "vault-1"`)
+
+		checker, err = newFileChecker("status", path, WithFileJSONPath("status.leader", nil))
+		assert.NoError(t, err)
+		assert.EqualError(t, checker.Check(context.Background()), `JSON path "status.leader" not found: key "leader" not found`)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeFile(t, `{"status":`, 0o600)
+		checker, err := newFileChecker("status", path, WithFileJSONPath("status", nil))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse JSON: ")
+	})
+
+	t.Run("Permissions", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeFile(t, "token", 0o644)
+
+		checker, err := newFileChecker("secret", path, WithFilePermissions(0o644))
+		assert.NoError(t, err)
+		assert.NoError(t, checker.Check(context.Background()))
+
+		checker, err = newFileChecker("secret", path, WithFilePermissions(0o600))
+		assert.NoError(t, err)
+		assert.EqualError(t, checker.Check(context.Background()), "unexpected permissions: got 0644, expected 0600")
+	})
+
+	t.Run("Modified After", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeFile(t, "token", 0o600)
+		start := time.Now()
+		old := start.Add(-time.Hour)
+		assert.NoError(t, os.Chtimes(path, old, old))
+
+		checker, err := newFileChecker("secret", path, WithFileModifiedAfter(start))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not modified since ")
+
+		assert.NoError(t, os.Chtimes(path, time.Now(), start.Add(time.Second)))
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+}
+
+func TestLookupJSONPath(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{"servers": []any{map[string]any{"host": "db"}}}
+
+	value, err := lookupJSONPath(data, "servers.0.host")
+	assert.NoError(t, err)
+	assert.Equal(t, "db", value)
+
+	_, err = lookupJSONPath(data, "servers.1.host")
+	assert.EqualError(t, err, `invalid array index "1"`)
+
+	_, err = lookupJSONPath(data, "servers.0.host.name")
+	assert.EqualError(t, err, `key "name" not found`)
+}
@@ -1,11 +1,19 @@
 package checker
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"regexp"
 	"slices"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -13,6 +21,8 @@ const (
 	defaultHTTPTimeout       time.Duration = 1 * time.Second
 	defaultHTTPMethod        string        = http.MethodGet
 	defaultHTTPSkipTLSVerify bool          = false
+	defaultHTTPMaxRedirects  int           = 10
+	unixSocketScheme         string        = "unix://"
 )
 
 var defaultHTTPExpectedStatusCodes = []int{200}
@@ -21,19 +31,73 @@ var defaultHTTPExpectedStatusCodes = []int{200}
 type HTTPChecker struct {
 	name                string
 	address             string
+	requestURL          string // URL requested, differs from address for unix sockets
+	socketPath          string // Unix socket all requests are sent to
 	method              string
 	headers             map[string]string
+	body                []byte
+	contentType         string
+	basicAuthUser       string
+	basicAuthPassword   string
+	oauth2              *oauth2TokenSource
+	oauth2SkipTLSVerify bool // Skip verification of the token endpoint certificate, independent of skipTLSVerify
 	expectedStatusCodes []int
+	expectedHeaders     map[string]*regexp.Regexp
 	skipTLSVerify       bool
 	timeout             time.Duration
+	maxRedirects        int
+	maxResponseTime     time.Duration
+	resolve             map[string]string
+	allAddresses        bool
+	minAddresses        int
+	lastResults         AddressResults
 	client              *http.Client
 }
 
-func (c *HTTPChecker) Address() string { return c.address }
-func (c *HTTPChecker) Name() string    { return c.name }
-func (c *HTTPChecker) Type() string    { return HTTP.String() }
+func (c *HTTPChecker) Address() string      { return c.address }
+func (c *HTTPChecker) Name() string         { return c.name }
+func (c *HTTPChecker) Type() string         { return HTTP.String() }
+func (c *HTTPChecker) Details() []slog.Attr { return addressDetails(c.lastResults) }
 func (c *HTTPChecker) Check(ctx context.Context) error {
-	req, err := http.NewRequestWithContext(ctx, c.method, c.address, nil)
+	c.lastResults = nil
+
+	if !c.allAddresses || c.socketPath != "" {
+		return c.check(ctx)
+	}
+
+	u, err := url.Parse(c.address)
+	if err != nil {
+		return fmt.Errorf("failed to parse address: %w", err)
+	}
+
+	host, port := u.Hostname(), u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	ips, err := lookupTargetIPs(ctx, host, port, c.resolve)
+	if err != nil {
+		return err
+	}
+
+	c.lastResults, err = checkAllAddresses(ctx, ips, c.minAddresses, true, func(ctx context.Context, ip net.IP) error {
+		return c.check(withDialOverride(ctx, net.JoinHostPort(host, port), ip.String()))
+	})
+	return err
+}
+
+// check sends a single request and validates the response.
+func (c *HTTPChecker) check(ctx context.Context) error {
+	// Create a new body reader for each attempt so retries always send the full body
+	var body io.Reader
+	if len(c.body) > 0 {
+		body = bytes.NewReader(c.body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, c.method, c.requestURL, body)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -42,17 +106,48 @@ func (c *HTTPChecker) Check(ctx context.Context) error {
 		req.Header.Add(key, value)
 	}
 
+	if c.contentType != "" {
+		req.Header.Set("Content-Type", c.contentType)
+	}
+
+	if c.basicAuthUser != "" {
+		req.SetBasicAuth(c.basicAuthUser, c.basicAuthPassword)
+	}
+
+	if c.oauth2 != nil {
+		token, err := c.oauth2.Token(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to obtain OAuth2 token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	start := time.Now()
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return fmt.Errorf("HTTP request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	latency := time.Since(start)
 
-	if slices.Contains(c.expectedStatusCodes, resp.StatusCode) {
-		return nil
+	// Fetch a new token on the next attempt if the target rejected the current one
+	if c.oauth2 != nil && resp.StatusCode == http.StatusUnauthorized {
+		c.oauth2.Invalidate()
 	}
 
-	return fmt.Errorf("unexpected status code: got %d, expected one of %v", resp.StatusCode, c.expectedStatusCodes)
+	if !slices.Contains(c.expectedStatusCodes, resp.StatusCode) {
+		return fmt.Errorf("unexpected status code: got %d, expected one of %v", resp.StatusCode, c.expectedStatusCodes)
+	}
+
+	if err := c.checkHeaders(resp.Header); err != nil {
+		return err
+	}
+
+	if c.maxResponseTime > 0 && latency > c.maxResponseTime {
+		return fmt.Errorf("response too slow: took %s, expected at most %s", latency.Round(time.Millisecond), c.maxResponseTime)
+	}
+
+	return nil
 }
 
 // newHTTPChecker creates a new HTTPChecker with functional options.
@@ -60,30 +155,110 @@ func newHTTPChecker(name, address string, opts ...Option) (*HTTPChecker, error)
 	checker := &HTTPChecker{
 		name:                name,
 		address:             address,
+		requestURL:          address,
 		method:              defaultHTTPMethod,
 		headers:             make(map[string]string),
 		expectedStatusCodes: defaultHTTPExpectedStatusCodes,
 		skipTLSVerify:       defaultHTTPSkipTLSVerify,
 		timeout:             defaultHTTPTimeout,
+		maxRedirects:        defaultHTTPMaxRedirects,
 	}
 
 	for _, opt := range opts {
 		opt.apply(checker)
 	}
 
-	checker.client = &http.Client{
-		Timeout: checker.timeout,
-		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: checker.skipTLSVerify,
-			},
+	transport := &http.Transport{
+		Proxy:       http.ProxyFromEnvironment,
+		DialContext: overrideDialContext(&net.Dialer{}, checker.resolve),
+		// Connections must not be reused across addresses when every address is checked
+		DisableKeepAlives: checker.allAddresses,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: checker.skipTLSVerify,
 		},
 	}
 
+	if socketPath, requestURL, ok := parseUnixSocketURL(address); ok {
+		checker.socketPath = socketPath
+		checker.requestURL = requestURL
+		transport.Proxy = nil
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+		}
+	}
+
+	checker.client = &http.Client{
+		Timeout:       checker.timeout,
+		CheckRedirect: checker.checkRedirect,
+		Transport:     transport,
+	}
+
+	// The token endpoint is a different server, so it must not use the unix socket or the dial overrides of the target
+	if checker.oauth2 != nil {
+		tokenTransport := http.DefaultTransport.(*http.Transport).Clone()
+		tokenTransport.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: checker.oauth2SkipTLSVerify,
+		}
+		checker.oauth2.client = &http.Client{
+			Timeout:   checker.timeout,
+			Transport: tokenTransport,
+		}
+	}
+
 	return checker, nil
 }
 
+// parseUnixSocketURL splits an address like "unix:///var/run/app.sock:/healthz" into the socket path and
+// the URL requested over it. The request path defaults to "/".
+func parseUnixSocketURL(address string) (socketPath, requestURL string, ok bool) {
+	rest, ok := strings.CutPrefix(address, unixSocketScheme)
+	if !ok {
+		return "", "", false
+	}
+
+	socketPath, path := rest, "/"
+	if i := strings.Index(rest, ":/"); i >= 0 {
+		socketPath, path = rest[:i], rest[i+1:]
+	}
+
+	return socketPath, "http://localhost" + path, true
+}
+
+// checkHeaders verifies that every expected header is present and at least one of its values matches the pattern.
+func (c *HTTPChecker) checkHeaders(header http.Header) error {
+	names := make([]string, 0, len(c.expectedHeaders))
+	for name := range c.expectedHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		pattern := c.expectedHeaders[name]
+
+		values := header.Values(name)
+		if len(values) == 0 {
+			return fmt.Errorf("missing header %q", name)
+		}
+
+		if !slices.ContainsFunc(values, pattern.MatchString) {
+			return fmt.Errorf("unexpected value for header %q: got %q, expected to match %q", name, strings.Join(values, ", "), pattern.String())
+		}
+	}
+
+	return nil
+}
+
+// checkRedirect enforces the redirect policy. With a limit of zero the redirect response itself is checked.
+func (c *HTTPChecker) checkRedirect(req *http.Request, via []*http.Request) error {
+	if c.maxRedirects == 0 {
+		return http.ErrUseLastResponse
+	}
+	if len(via) > c.maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", c.maxRedirects)
+	}
+	return nil
+}
+
 // WithHTTPMethod sets the HTTP method for the HTTPChecker.
 func WithHTTPMethod(method string) Option {
 	return OptionFunc(func(c Checker) {
@@ -102,6 +277,59 @@ func WithHTTPHeaders(headers map[string]string) Option {
 	})
 }
 
+// WithHTTPBody sets the request body for the HTTPChecker.
+func WithHTTPBody(body []byte) Option {
+	return OptionFunc(func(c Checker) {
+		if httpChecker, ok := c.(*HTTPChecker); ok {
+			httpChecker.body = body
+		}
+	})
+}
+
+// WithHTTPContentType sets the Content-Type header of the request for the HTTPChecker.
+func WithHTTPContentType(contentType string) Option {
+	return OptionFunc(func(c Checker) {
+		if httpChecker, ok := c.(*HTTPChecker); ok {
+			httpChecker.contentType = contentType
+		}
+	})
+}
+
+// WithHTTPBasicAuth sets the credentials for HTTP basic authentication for the HTTPChecker.
+func WithHTTPBasicAuth(user, password string) Option {
+	return OptionFunc(func(c Checker) {
+		if httpChecker, ok := c.(*HTTPChecker); ok {
+			httpChecker.basicAuthUser = user
+			httpChecker.basicAuthPassword = password
+		}
+	})
+}
+
+// WithHTTPOAuth2ClientCredentials configures the HTTPChecker to send a bearer token obtained
+// with the OAuth2 client credentials grant. The token is cached and refreshed when it expires.
+func WithHTTPOAuth2ClientCredentials(tokenURL, clientID, clientSecret string, scopes []string) Option {
+	return OptionFunc(func(c Checker) {
+		if httpChecker, ok := c.(*HTTPChecker); ok {
+			httpChecker.oauth2 = &oauth2TokenSource{
+				tokenURL:     tokenURL,
+				clientID:     clientID,
+				clientSecret: clientSecret,
+				scopes:       scopes,
+			}
+		}
+	})
+}
+
+// WithHTTPOAuth2SkipTLSVerify disables the verification of the token endpoint certificate. The client
+// credentials are sent to the token endpoint, so it is verified even if WithHTTPSkipTLSVerify is set.
+func WithHTTPOAuth2SkipTLSVerify(skip bool) Option {
+	return OptionFunc(func(c Checker) {
+		if httpChecker, ok := c.(*HTTPChecker); ok {
+			httpChecker.oauth2SkipTLSVerify = skip
+		}
+	})
+}
+
 // WithExpectedStatusCodes sets the expected status codes for the HTTPChecker.
 func WithExpectedStatusCodes(codes []int) Option {
 	return OptionFunc(func(c Checker) {
@@ -111,6 +339,16 @@ func WithExpectedStatusCodes(codes []int) Option {
 	})
 }
 
+// WithHTTPExpectedHeaders sets the response headers the HTTPChecker expects. Each header must be present
+// and at least one of its values must match the regular expression.
+func WithHTTPExpectedHeaders(headers map[string]*regexp.Regexp) Option {
+	return OptionFunc(func(c Checker) {
+		if httpChecker, ok := c.(*HTTPChecker); ok {
+			httpChecker.expectedHeaders = headers
+		}
+	})
+}
+
 // WithHTTPSkipTLSVerify sets the TLS verification flag for the HTTPChecker.
 func WithHTTPSkipTLSVerify(skip bool) Option {
 	return OptionFunc(func(c Checker) {
@@ -120,6 +358,47 @@ func WithHTTPSkipTLSVerify(skip bool) Option {
 	})
 }
 
+// WithHTTPMaxRedirects sets the maximum number of redirects the HTTPChecker follows.
+// Zero disables following redirects, so the redirect response itself is checked.
+func WithHTTPMaxRedirects(maxRedirects int) Option {
+	return OptionFunc(func(c Checker) {
+		if httpChecker, ok := c.(*HTTPChecker); ok {
+			httpChecker.maxRedirects = maxRedirects
+		}
+	})
+}
+
+// WithHTTPMaxResponseTime sets the maximum time the HTTPChecker waits for the response headers
+// before the target is considered not ready. Zero disables the threshold.
+func WithHTTPMaxResponseTime(maxResponseTime time.Duration) Option {
+	return OptionFunc(func(c Checker) {
+		if httpChecker, ok := c.(*HTTPChecker); ok {
+			httpChecker.maxResponseTime = maxResponseTime
+		}
+	})
+}
+
+// WithHTTPResolve sets "host:port" to IP overrides for the HTTPChecker, similar to curl's --resolve.
+// The Host header and the TLS server name are still derived from the address.
+func WithHTTPResolve(overrides map[string]string) Option {
+	return OptionFunc(func(c Checker) {
+		if httpChecker, ok := c.(*HTTPChecker); ok {
+			httpChecker.resolve = overrides
+		}
+	})
+}
+
+// WithHTTPAllAddresses makes the HTTPChecker send the request to every resolved IP address of the host.
+// The check passes if at least minAddresses addresses are ready; zero requires all of them.
+func WithHTTPAllAddresses(minAddresses int) Option {
+	return OptionFunc(func(c Checker) {
+		if httpChecker, ok := c.(*HTTPChecker); ok {
+			httpChecker.allAddresses = true
+			httpChecker.minAddresses = minAddresses
+		}
+	})
+}
+
 // WithHTTPTimeout sets the timeout for the HTTPChecker.
 func WithHTTPTimeout(timeout time.Duration) Option {
 	return OptionFunc(func(c Checker) {
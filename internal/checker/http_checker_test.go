@@ -3,8 +3,12 @@ package checker
 import (
 	"context"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"regexp"
 	"testing"
 	"time"
 
@@ -172,4 +176,321 @@ func TestHTTPChecker(t *testing.T) {
 		err = checker.Check(ctx)
 		assert.NoError(t, err)
 	})
+
+	t.Run("Request body is sent on every attempt", func(t *testing.T) {
+		t.Parallel()
+
+		var bodies []string
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			if r.Header.Get("Content-Type") != "application/json" {
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		})
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		checker, err := newHTTPChecker("example", server.URL,
+			WithHTTPMethod(http.MethodPost),
+			WithHTTPBody([]byte(`{"query":"{__typename}"}`)),
+			WithHTTPContentType("application/json"),
+		)
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		assert.NoError(t, checker.Check(ctx))
+		assert.NoError(t, checker.Check(ctx))
+		assert.Equal(t, []string{`{"query":"{__typename}"}`, `{"query":"{__typename}"}`}, bodies)
+	})
+
+	t.Run("Redirects are not followed", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/login" {
+				http.Redirect(w, r, "/sso", http.StatusFound)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		checker, err := newHTTPChecker("example", server.URL+"/login", WithHTTPMaxRedirects(0), WithExpectedStatusCodes([]int{302}))
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		err = checker.Check(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Redirects are followed by default", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/login" {
+				http.Redirect(w, r, "/sso", http.StatusFound)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		checker, err := newHTTPChecker("example", server.URL+"/login")
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		err = checker.Check(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Too many redirects", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/a":
+				http.Redirect(w, r, "/b", http.StatusFound)
+			case "/b":
+				http.Redirect(w, r, "/c", http.StatusFound)
+			default:
+				w.WriteHeader(http.StatusOK)
+			}
+		}))
+		defer server.Close()
+
+		checker, err := newHTTPChecker("example", server.URL+"/a", WithHTTPMaxRedirects(1))
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		err = checker.Check(ctx)
+		assert.EqualError(t, err, "HTTP request failed: Get \"/c\": stopped after 1 redirects")
+	})
+
+	t.Run("Response slower than max response time", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		checker, err := newHTTPChecker("example", server.URL, WithHTTPMaxResponseTime(10*time.Millisecond))
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		err = checker.Check(ctx)
+		assert.Error(t, err)
+		assert.Regexp(t, `^response too slow: took \d+ms, expected at most 10ms$`, err.Error())
+	})
+
+	t.Run("Response within max response time", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		checker, err := newHTTPChecker("example", server.URL, WithHTTPMaxResponseTime(500*time.Millisecond))
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		err = checker.Check(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Expected headers match", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-App-Version", "1.2.3")
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		checker, err := newHTTPChecker("example", server.URL, WithHTTPExpectedHeaders(map[string]*regexp.Regexp{
+			"X-App-Version": regexp.MustCompile(`^1\.2\.3$`),
+		}))
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		err = checker.Check(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Expected header mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-App-Version", "1.2.2")
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		checker, err := newHTTPChecker("example", server.URL, WithHTTPExpectedHeaders(map[string]*regexp.Regexp{
+			"X-App-Version": regexp.MustCompile(`^1\.2\.3$`),
+		}))
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		err = checker.Check(ctx)
+		assert.EqualError(t, err, `unexpected value for header "X-App-Version": got "1.2.2", expected to match "^1\\.2\\.3$"`)
+	})
+
+	t.Run("Expected header missing", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		checker, err := newHTTPChecker("example", server.URL, WithHTTPExpectedHeaders(map[string]*regexp.Regexp{
+			"X-Ready": regexp.MustCompile(`true`),
+		}))
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		err = checker.Check(ctx)
+		assert.EqualError(t, err, `missing header "X-Ready"`)
+	})
+
+	t.Run("Resolve override keeps host and server name", func(t *testing.T) {
+		t.Parallel()
+
+		var host, serverName string
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host = r.Host
+			serverName = r.TLS.ServerName
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+		address := fmt.Sprintf("https://backend.portpatrol.test:%s/healthz", port)
+
+		checker, err := newHTTPChecker("example", address,
+			WithHTTPSkipTLSVerify(true),
+			WithHTTPResolve(map[string]string{"backend.portpatrol.test:" + port: "127.0.0.1"}),
+		)
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		err = checker.Check(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "backend.portpatrol.test:"+port, host)
+		assert.Equal(t, "backend.portpatrol.test", serverName)
+	})
+
+	t.Run("Every address is checked", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+		address := fmt.Sprintf("http://web.portpatrol.test:%s/", port)
+
+		checker, err := newHTTPChecker("example", address,
+			WithHTTPResolve(map[string]string{"web.portpatrol.test:" + port: "127.0.0.1"}),
+			WithHTTPAllAddresses(0),
+		)
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		err = checker.Check(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "addresses=127.0.0.1=ok", checker.Details()[0].String())
+
+		// Results of the previous check must not be reported next to a lookup error
+		checker.resolve = nil
+		assert.Error(t, checker.Check(ctx))
+		assert.Empty(t, checker.Details())
+	})
+}
+
+func TestHTTPChecker_UnixSocket(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to start unix socket server: %q", err)
+	}
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.RequestURI() != "/healthz?full=1" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}),
+	}
+	go func() { _ = server.Serve(ln) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	t.Run("Request Path", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newHTTPChecker("envoy", "unix://"+path+":/healthz?full=1")
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Default Path", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newHTTPChecker("envoy", "unix://"+path)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "unexpected status code: got 404, expected one of [200]")
+	})
+}
+
+func TestParseUnixSocketURL(t *testing.T) {
+	t.Parallel()
+
+	socketPath, requestURL, ok := parseUnixSocketURL("unix:///var/run/app.sock:/healthz")
+	assert.True(t, ok)
+	assert.Equal(t, "/var/run/app.sock", socketPath)
+	assert.Equal(t, "http://localhost/healthz", requestURL)
+
+	socketPath, requestURL, ok = parseUnixSocketURL("unix:///var/run/docker.sock")
+	assert.True(t, ok)
+	assert.Equal(t, "/var/run/docker.sock", socketPath)
+	assert.Equal(t, "http://localhost/", requestURL)
+
+	_, _, ok = parseUnixSocketURL("http://example.com/healthz")
+	assert.False(t, ok)
 }
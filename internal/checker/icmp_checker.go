@@ -2,74 +2,225 @@ package checker
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net"
 	"os"
-	"sync/atomic"
+	"strings"
 	"time"
 )
 
 const (
 	defaultICMPReadTimeout  time.Duration = 1 * time.Second
 	defaultICMPWriteTimeout time.Duration = 1 * time.Second
+	defaultICMPCount        int           = 1
 )
 
 // ICMPChecker implements the Checker interface for ICMP checks.
 type ICMPChecker struct {
-	name         string
-	address      string
-	readTimeout  time.Duration
-	writeTimeout time.Duration
-	protocol     Protocol
+	name          string
+	address       string
+	readTimeout   time.Duration
+	writeTimeout  time.Duration
+	count         int           // Echo requests sent per check
+	maxLoss       int           // Maximum packet loss in percent
+	maxRTT        time.Duration // Maximum round-trip time, zero disables the limit
+	rttPercentile int           // Percentile compared against maxRTT, zero uses the average
+	packet        packetOptions
+	lastStats     PingStats
+	allAddresses  bool
+	minAddresses  int
+	privileged    *bool         // nil auto-detects raw socket support
+	listeners     *listenerPool // Shared ICMP listeners, a private pool is created if nil
+	lastResults   AddressResults
+	protocol      Protocol
 }
 
 func (c *ICMPChecker) Address() string { return c.address }
 func (c *ICMPChecker) Name() string    { return c.name }
 func (c *ICMPChecker) Type() string    { return ICMP.String() }
 
+// Details returns the ping summary of the last burst and the per-address results.
+func (c *ICMPChecker) Details() []slog.Attr {
+	details := addressDetails(c.lastResults)
+	if c.count > 1 && c.lastStats.Sent > 0 {
+		details = append(details, slog.String("ping", c.lastStats.String()))
+	}
+	return details
+}
+
 func (c *ICMPChecker) Check(ctx context.Context) error {
-	dst, err := net.ResolveIPAddr(c.protocol.Network(), c.address)
+	c.lastResults = nil
+	c.lastStats = PingStats{}
+
+	if c.protocol == nil {
+		protocol, err := newProtocol(c.address, c.privileged == nil || *c.privileged, c.packet)
+		if err != nil {
+			return fmt.Errorf("failed to create ICMP protocol: %w", err)
+		}
+		c.protocol = protocol
+	}
+
+	if c.allAddresses {
+		return c.checkAllAddresses(ctx)
+	}
+
+	dst, err := net.ResolveIPAddr(resolveNetwork(c.protocol.Network()), c.address)
 	if err != nil {
 		return fmt.Errorf("failed to resolve IP address '%s': %w", c.address, err)
 	}
 
-	conn, err := c.protocol.ListenPacket(ctx, c.protocol.Network(), "")
+	c.lastStats, err = c.ping(ctx, c.protocol, dst)
+	return err
+}
+
+// checkAllAddresses pings every resolved IP address, using the protocol matching each address family.
+// The addresses are pinged one after another to keep the burst statistics of each address meaningful.
+func (c *ICMPChecker) checkAllAddresses(ctx context.Context) error {
+	ips, err := lookupIPs(ctx, c.address)
 	if err != nil {
-		return fmt.Errorf("failed to listen for ICMP packets: %w", err)
+		return err
 	}
-	defer conn.Close()
 
-	id := uint16(os.Getpid() & 0xffff)                       // Create a unique identifier
-	seq := uint16(atomic.AddUint32(new(uint32), 1) & 0xffff) // Create a unique sequence number
+	c.lastResults, err = checkAllAddresses(ctx, ips, c.minAddresses, false, func(ctx context.Context, ip net.IP) error {
+		protocol, err := newProtocol(ip.String(), c.protocol.Privileged(), c.packet)
+		if err != nil {
+			return err
+		}
+		_, err = c.ping(ctx, protocol, &net.IPAddr{IP: ip})
+		return err
+	})
+	return err
+}
 
-	msg, err := c.protocol.MakeRequest(id, seq)
+// ping sends a burst of ICMP echo requests to dst and checks the packet loss and round-trip times.
+// Each failed echo counts as a lost packet; if no reply was received at all, the last error is returned.
+func (c *ICMPChecker) ping(ctx context.Context, protocol Protocol, dst *net.IPAddr) (PingStats, error) {
+	listener, err := c.listen(ctx, protocol)
 	if err != nil {
-		return fmt.Errorf("failed to create ICMP request: %w", err)
+		return PingStats{}, fmt.Errorf("failed to listen for ICMP packets: %w", err)
 	}
+	defer c.listeners.release(listener)
+
+	id := uint16(os.Getpid() & 0xffff) // Create a unique identifier
+
+	var stats PingStats
+	var lastErr error
+	for range max(c.count, 1) {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
 
-	if err := conn.SetWriteDeadline(time.Now().Add(c.writeTimeout)); err != nil {
-		return fmt.Errorf("failed to set write deadline: %w", err)
+		stats.Sent++
+		rtt, err := c.echo(ctx, listener, protocol, dst, id, nextICMPSequence())
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		stats.Received++
+		stats.RTTs = append(stats.RTTs, rtt)
 	}
 
-	if _, err := conn.WriteTo(msg, dst); err != nil {
-		return fmt.Errorf("failed to send ICMP request: %w", err)
+	if stats.Received == 0 {
+		if stats.Sent == 1 {
+			return stats, lastErr
+		}
+		return stats, fmt.Errorf("no reply to %d ICMP requests: %w", stats.Sent, lastErr)
 	}
 
-	if err := conn.SetReadDeadline(time.Now().Add(c.readTimeout)); err != nil {
-		return fmt.Errorf("failed to set read deadline: %w", err)
+	if loss := stats.Loss(); loss > float64(c.maxLoss) {
+		return stats, fmt.Errorf("packet loss of %.0f%% exceeds %d%%: %s", loss, c.maxLoss, stats)
 	}
 
-	reply := make([]byte, 1500)
-	n, _, err := conn.ReadFrom(reply)
+	if c.maxRTT > 0 {
+		rtt, label := stats.Avg(), "average"
+		if c.rttPercentile > 0 {
+			rtt, label = stats.Percentile(c.rttPercentile), fmt.Sprintf("p%d", c.rttPercentile)
+		}
+		if rtt > c.maxRTT {
+			return stats, fmt.Errorf("%s round-trip time %s exceeds %s: %s", label, rtt, c.maxRTT, stats)
+		}
+	}
+
+	return stats, nil
+}
+
+// echo sends a single ICMP echo request to dst and returns the round-trip time of its validated reply.
+// The shared listener only hands over echo replies with the request's sequence number; those that do not
+// validate, e.g. replies to other processes using the same sequence number, are skipped until the read
+// timeout expires.
+func (c *ICMPChecker) echo(ctx context.Context, listener *icmpListener, protocol Protocol, dst *net.IPAddr, id, seq uint16) (time.Duration, error) {
+	msg, err := protocol.MakeRequest(id, seq)
 	if err != nil {
-		return fmt.Errorf("failed to read ICMP reply: %w", err)
+		return 0, fmt.Errorf("failed to create ICMP request: %w", err)
 	}
 
-	if err := c.protocol.ValidateReply(reply[:n], id, seq); err != nil {
-		return fmt.Errorf("failed to validate ICMP reply: %w", err)
+	// Register before sending, so a fast reply cannot be read before the check waits for it.
+	waiter, err := listener.register(seq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read ICMP reply: %w", err)
 	}
+	defer listener.unregister(waiter)
 
-	return nil
+	start := time.Now()
+	if err := listener.write(msg, dst, c.writeTimeout); err != nil {
+		return 0, err
+	}
+
+	timer := time.NewTimer(c.readTimeout)
+	defer timer.Stop()
+
+	var validateErr error
+	for {
+		select {
+		case reply := <-waiter.replies:
+			if err := protocol.ValidateReply(reply.data, id, seq); err != nil {
+				validateErr = err
+				continue
+			}
+			return reply.received.Sub(start), nil
+		case <-listener.done:
+			return 0, fmt.Errorf("failed to read ICMP reply: %w", listener.err)
+		case <-timer.C:
+			if validateErr != nil {
+				return 0, fmt.Errorf("failed to validate ICMP reply: %w", validateErr)
+			}
+			return 0, fmt.Errorf("failed to read ICMP reply: %w", os.ErrDeadlineExceeded)
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
+
+// listen acquires the shared listener for protocol. When the privilege mode is auto-detected and the raw
+// socket is not permitted, the protocol is switched to unprivileged datagram sockets for this and later checks.
+func (c *ICMPChecker) listen(ctx context.Context, protocol Protocol) (*icmpListener, error) {
+	if c.listeners == nil {
+		c.listeners = newListenerPool()
+	}
+
+	open := func() (net.PacketConn, error) {
+		return protocol.ListenPacket(ctx, protocol.Network(), "")
+	}
+
+	listener, err := c.listeners.acquire(c.packet.listenerKey(protocol.Network()), open)
+	if err == nil || c.privileged != nil || !protocol.Privileged() || !errors.Is(err, os.ErrPermission) {
+		return listener, err
+	}
+
+	protocol.SetPrivileged(false)
+	if protocol != c.protocol {
+		c.protocol.SetPrivileged(false)
+	}
+	return c.listeners.acquire(c.packet.listenerKey(protocol.Network()), open)
+}
+
+// resolveNetwork returns the IP network used to resolve addresses for an ICMP listen network,
+// e.g. "ip4" for "ip4:icmp" or "udp4".
+func resolveNetwork(network string) string {
+	network, _, _ = strings.Cut(network, ":")
+	return strings.Replace(network, "udp", "ip", 1)
 }
 
 // newICMPChecker initializes a new ICMPChecker with functional options.
@@ -79,13 +230,24 @@ func newICMPChecker(name, address string, opts ...Option) (*ICMPChecker, error)
 		address:      address,
 		readTimeout:  defaultICMPReadTimeout,
 		writeTimeout: defaultICMPWriteTimeout,
+		count:        defaultICMPCount,
+		listeners:    defaultListenerPool,
 	}
 
 	for _, opt := range opts {
 		opt.apply(checker)
 	}
 
-	protocol, err := newProtocol(checker.address)
+	// The protocol of a hostname depends on its addresses, so it is created by the first check. This keeps
+	// building checkers, e.g. for --dry-run, free of DNS lookups.
+	if net.ParseIP(checker.address) == nil {
+		if !isHostname(checker.address) {
+			return nil, fmt.Errorf("failed to create ICMP protocol: invalid address: %s", checker.address)
+		}
+		return checker, nil
+	}
+
+	protocol, err := newProtocol(checker.address, checker.privileged == nil || *checker.privileged, checker.packet)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create ICMP protocol: %w", err)
 	}
@@ -94,6 +256,30 @@ func newICMPChecker(name, address string, opts ...Option) (*ICMPChecker, error)
 	return checker, nil
 }
 
+// isHostname reports whether host is a syntactically valid DNS name, without resolving it. Names ending in a
+// numeric label are rejected as invalid IP addresses.
+func isHostname(host string) bool {
+	host = strings.TrimSuffix(host, ".")
+	if host == "" || len(host) > 253 {
+		return false
+	}
+
+	labels := strings.Split(host, ".")
+	for _, label := range labels {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return false
+			}
+		}
+	}
+
+	// Top-level domains are never numeric, so such names are malformed IP addresses like 300.300.300.300
+	return strings.Trim(labels[len(labels)-1], "0123456789") != ""
+}
+
 // WithICMPReadTimeout sets the read timeout for the ICMPChecker.
 func WithICMPReadTimeout(timeout time.Duration) Option {
 	return OptionFunc(func(c Checker) {
@@ -111,3 +297,82 @@ func WithICMPWriteTimeout(timeout time.Duration) Option {
 		}
 	})
 }
+
+// WithICMPAllAddresses makes the ICMPChecker ping every resolved IP address of the host. The check passes
+// if at least minAddresses addresses reply; zero requires all of them.
+func WithICMPAllAddresses(minAddresses int) Option {
+	return OptionFunc(func(c Checker) {
+		if icmpChecker, ok := c.(*ICMPChecker); ok {
+			icmpChecker.allAddresses = true
+			icmpChecker.minAddresses = minAddresses
+		}
+	})
+}
+
+// WithICMPPrivileged selects raw ICMP sockets (true), which require CAP_NET_RAW, or unprivileged
+// datagram sockets (false). Without this option raw sockets are tried first, falling back to
+// datagram sockets when they are not permitted.
+func WithICMPPrivileged(privileged bool) Option {
+	return OptionFunc(func(c Checker) {
+		if icmpChecker, ok := c.(*ICMPChecker); ok {
+			icmpChecker.privileged = &privileged
+		}
+	})
+}
+
+// WithICMPCount sets the number of echo requests the ICMPChecker sends per check.
+func WithICMPCount(count int) Option {
+	return OptionFunc(func(c Checker) {
+		if icmpChecker, ok := c.(*ICMPChecker); ok {
+			icmpChecker.count = count
+		}
+	})
+}
+
+// WithICMPMaxLoss sets the maximum packet loss in percent tolerated by the ICMPChecker.
+func WithICMPMaxLoss(percent int) Option {
+	return OptionFunc(func(c Checker) {
+		if icmpChecker, ok := c.(*ICMPChecker); ok {
+			icmpChecker.maxLoss = percent
+		}
+	})
+}
+
+// WithICMPMaxRTT sets the maximum round-trip time tolerated by the ICMPChecker. The average round-trip
+// time is compared, or the given percentile (1-100) of the round-trip times if percentile is positive.
+func WithICMPMaxRTT(maxRTT time.Duration, percentile int) Option {
+	return OptionFunc(func(c Checker) {
+		if icmpChecker, ok := c.(*ICMPChecker); ok {
+			icmpChecker.maxRTT = maxRTT
+			icmpChecker.rttPercentile = percentile
+		}
+	})
+}
+
+// WithICMPPacketSize sets the payload size in bytes of the echo requests sent by the ICMPChecker.
+func WithICMPPacketSize(size int) Option {
+	return OptionFunc(func(c Checker) {
+		if icmpChecker, ok := c.(*ICMPChecker); ok {
+			icmpChecker.packet.size = size
+		}
+	})
+}
+
+// WithICMPTTL sets the time to live (hop limit for IPv6) of the echo requests sent by the ICMPChecker.
+func WithICMPTTL(ttl int) Option {
+	return OptionFunc(func(c Checker) {
+		if icmpChecker, ok := c.(*ICMPChecker); ok {
+			icmpChecker.packet.ttl = ttl
+		}
+	})
+}
+
+// WithICMPDontFragment makes the ICMPChecker send echo requests that must not be fragmented, so requests
+// larger than the path MTU fail. Only supported on Linux.
+func WithICMPDontFragment(dontFragment bool) Option {
+	return OptionFunc(func(c Checker) {
+		if icmpChecker, ok := c.(*ICMPChecker); ok {
+			icmpChecker.packet.dontFragment = dontFragment
+		}
+	})
+}
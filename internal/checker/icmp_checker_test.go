@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"os"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -16,6 +19,16 @@ import (
 	"golang.org/x/net/ipv4"
 )
 
+// makeEchoReply is used as the request of mock protocols whose connections loop written packets back,
+// so the shared listener reads them as the echo reply to the request.
+func makeEchoReply(id, seq uint16) ([]byte, error) {
+	msg := icmp.Message{
+		Type: ipv4.ICMPTypeEchoReply,
+		Body: &icmp.Echo{ID: int(id), Seq: int(seq)},
+	}
+	return msg.Marshal(nil)
+}
+
 // TestNewICMPCheckerValidIPv4 tests creating an ICMPChecker with a valid IPv4 address.
 func TestNewICMPCheckerValidIPv4(t *testing.T) {
 	t.Parallel()
@@ -33,9 +46,32 @@ func TestNewICMPCheckerValidIPv4(t *testing.T) {
 func TestNewICMPCheckerInvalidAddress(t *testing.T) {
 	t.Parallel()
 
-	_, err := newICMPChecker("InvalidAddress", "invalid-address")
+	_, err := newICMPChecker("InvalidAddress", "://invalid-url")
 	assert.Error(t, err)
-	assert.Equal(t, err.Error(), "failed to create ICMP protocol: invalid or unresolvable address: invalid-address")
+	assert.Equal(t, err.Error(), "failed to create ICMP protocol: invalid address: ://invalid-url")
+}
+
+// TestICMPCheckerCheckUnresolvableAddress tests that hostnames are resolved by the check instead of the constructor.
+func TestICMPCheckerCheckUnresolvableAddress(t *testing.T) {
+	t.Parallel()
+
+	checker, err := newICMPChecker("UnresolvableAddress", "invalid-address")
+	assert.NoError(t, err)
+	assert.Nil(t, checker.protocol)
+
+	err = checker.Check(context.Background())
+	assert.EqualError(t, err, "failed to create ICMP protocol: invalid or unresolvable address: invalid-address")
+}
+
+func TestIsHostname(t *testing.T) {
+	t.Parallel()
+
+	for _, host := range []string{"localhost", "db.cluster.local", "db.cluster.local.", "_sip.example.com", "10-0-0-1.svc"} {
+		assert.True(t, isHostname(host), host)
+	}
+	for _, host := range []string{"", "://invalid-url", "-db.local", "db..local", "db local", strings.Repeat("a", 64) + ".local", "300.300.300.300", "10.0.0.256.", "1234"} {
+		assert.False(t, isHostname(host), host)
+	}
 }
 
 // TestICMPCheckerCheckSuccess tests successful ICMP checking.
@@ -45,7 +81,7 @@ func TestICMPCheckerCheckSuccess(t *testing.T) {
 	mockProtocol := &testutils.MockProtocol{
 		MakeRequestFunc: func(id, seq uint16) ([]byte, error) {
 			msg := icmp.Message{
-				Type: ipv4.ICMPTypeEcho,
+				Type: ipv4.ICMPTypeEchoReply,
 				Code: 0,
 				Body: &icmp.Echo{
 					ID:   int(id),
@@ -215,9 +251,6 @@ func TestICMPCheckerCheckWriteDeadlineError(t *testing.T) {
 		NetworkFunc: func() string {
 			return "ip4:icmp"
 		},
-		SetDeadlineFunc: func(t time.Time) error {
-			return fmt.Errorf("mock write deadline error")
-		},
 		ListenPacketFunc: func(ctx context.Context, network, address string) (net.PacketConn, error) {
 			return &testutils.MockPacketConn{
 				WriteToFunc: func(b []byte, addr net.Addr) (int, error) {
@@ -315,33 +348,27 @@ func TestICMPCheckerSetWriteDeadlineError(t *testing.T) {
 	assert.EqualError(t, err, "failed to set write deadline: mock write deadline error")
 }
 
-func TestICMPCheckerSetReadDeadlineError(t *testing.T) {
+func TestICMPCheckerValidateReplyError(t *testing.T) {
 	t.Parallel()
 
 	mockProtocol := &testutils.MockProtocol{
-		MakeRequestFunc: func(id, seq uint16) ([]byte, error) {
-			return []byte{}, nil
-		},
+		MakeRequestFunc: makeEchoReply,
 		ValidateReplyFunc: func(reply []byte, id, seq uint16) error {
-			return nil
+			return fmt.Errorf("mock validation error")
 		},
 		NetworkFunc: func() string {
 			return "ip4:icmp"
 		},
 		ListenPacketFunc: func(ctx context.Context, network, address string) (net.PacketConn, error) {
-			return &testutils.MockPacketConn{
-				SetReadDeadlineFunc: func(t time.Time) error {
-					return fmt.Errorf("mock write deadline error")
-				},
-			}, nil
+			return &testutils.MockPacketConn{}, nil
 		},
 	}
 
 	checker := &ICMPChecker{
-		name:        "SetReadDeadlineErrorChecker",
+		name:        "ValidateReplyErrorChecker",
 		address:     "127.0.0.1",
 		protocol:    mockProtocol,
-		readTimeout: 2 * time.Second,
+		readTimeout: 100 * time.Millisecond,
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -349,38 +376,218 @@ func TestICMPCheckerSetReadDeadlineError(t *testing.T) {
 
 	err := checker.Check(ctx)
 	assert.Error(t, err)
-	assert.EqualError(t, err, "failed to set read deadline: mock write deadline error")
+	assert.EqualError(t, err, "failed to validate ICMP reply: mock validation error")
 }
 
-func TestICMPCheckerValidateReplyError(t *testing.T) {
+func TestICMPCheckerPrivileged(t *testing.T) {
 	t.Parallel()
 
-	mockProtocol := &testutils.MockProtocol{
-		MakeRequestFunc: func(id, seq uint16) ([]byte, error) {
-			return []byte{}, nil
-		},
-		ValidateReplyFunc: func(reply []byte, id, seq uint16) error {
-			return fmt.Errorf("mock validation error")
-		},
-		NetworkFunc: func() string {
-			return "ip4:icmp"
-		},
-		ListenPacketFunc: func(ctx context.Context, network, address string) (net.PacketConn, error) {
-			return &testutils.MockPacketConn{}, nil
-		},
+	// newMockProtocol returns a protocol whose raw socket is not permitted and records the listened networks.
+	newMockProtocol := func(networks *[]string) *testutils.MockProtocol {
+		privileged := true
+		return &testutils.MockProtocol{
+			MakeRequestFunc:   makeEchoReply,
+			PrivilegedFunc:    func() bool { return privileged },
+			SetPrivilegedFunc: func(p bool) { privileged = p },
+			NetworkFunc: func() string {
+				if privileged {
+					return "ip4:icmp"
+				}
+				return "udp4"
+			},
+			ListenPacketFunc: func(ctx context.Context, network, address string) (net.PacketConn, error) {
+				*networks = append(*networks, network)
+				if network == "ip4:icmp" {
+					return nil, &net.OpError{Op: "listen", Net: network, Err: os.NewSyscallError("socket", syscall.EPERM)}
+				}
+				return &testutils.MockPacketConn{}, nil
+			},
+		}
 	}
 
-	checker := &ICMPChecker{
-		name:        "ValidateReplyErrorChecker",
-		address:     "127.0.0.1",
-		protocol:    mockProtocol,
-		readTimeout: 2 * time.Second,
-	}
+	t.Run("Auto Falls Back To Unprivileged", func(t *testing.T) {
+		t.Parallel()
+
+		var networks []string
+		protocol := newMockProtocol(&networks)
+		checker := &ICMPChecker{
+			name:        "AutoChecker",
+			address:     "127.0.0.1",
+			protocol:    protocol,
+			readTimeout: time.Second,
+		}
+
+		assert.NoError(t, checker.Check(context.Background()))
+		assert.Equal(t, []string{"ip4:icmp", "udp4"}, networks)
+		assert.False(t, protocol.Privileged())
+
+		// The detected mode is kept for later checks.
+		assert.NoError(t, checker.Check(context.Background()))
+		assert.Equal(t, []string{"ip4:icmp", "udp4", "udp4"}, networks)
+	})
+
+	t.Run("Privileged Does Not Fall Back", func(t *testing.T) {
+		t.Parallel()
+
+		var networks []string
+		privileged := true
+		checker := &ICMPChecker{
+			name:        "PrivilegedChecker",
+			address:     "127.0.0.1",
+			protocol:    newMockProtocol(&networks),
+			readTimeout: time.Second,
+			privileged:  &privileged,
+		}
+
+		err := checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, os.ErrPermission)
+		assert.Equal(t, []string{"ip4:icmp"}, networks)
+	})
+
+	t.Run("Unprivileged Option", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newICMPChecker("UnprivilegedChecker", "127.0.0.1", WithICMPPrivileged(false))
+		assert.NoError(t, err)
+		assert.False(t, checker.protocol.Privileged())
+		assert.Equal(t, "udp4", checker.protocol.Network())
+	})
+
+	t.Run("Resolve Network", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "ip4", resolveNetwork("ip4:icmp"))
+		assert.Equal(t, "ip6", resolveNetwork("ip6:ipv6-icmp"))
+		assert.Equal(t, "ip4", resolveNetwork("udp4"))
+		assert.Equal(t, "ip6", resolveNetwork("udp6"))
+	})
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
+func TestICMPCheckerBurst(t *testing.T) {
+	t.Parallel()
 
-	err := checker.Check(ctx)
-	assert.Error(t, err)
-	assert.EqualError(t, err, "failed to validate ICMP reply: mock validation error")
+	// newMockProtocol returns a protocol that answers the n-th request after delays[n], or never if delays[n] is negative.
+	newMockProtocol := func(delays ...time.Duration) *testutils.MockProtocol {
+		return &testutils.MockProtocol{
+			MakeRequestFunc: makeEchoReply,
+			NetworkFunc:     func() string { return "ip4:icmp" },
+			ListenPacketFunc: func(ctx context.Context, network, address string) (net.PacketConn, error) {
+				replies := make(chan []byte, len(delays))
+				closed := make(chan struct{})
+				writes := 0
+				return &testutils.MockPacketConn{
+					WriteToFunc: func(b []byte, addr net.Addr) (int, error) {
+						delay := delays[writes%len(delays)]
+						writes++
+						if delay >= 0 {
+							time.AfterFunc(delay, func() { replies <- b })
+						}
+						return len(b), nil
+					},
+					ReadFromFunc: func(b []byte) (int, net.Addr, error) {
+						select {
+						case reply := <-replies:
+							return copy(b, reply), &net.IPAddr{}, nil
+						case <-closed:
+							return 0, nil, net.ErrClosed
+						}
+					},
+					CloseFunc: func() error {
+						close(closed)
+						return nil
+					},
+				}, nil
+			},
+		}
+	}
+
+	t.Run("Within Loss Threshold", func(t *testing.T) {
+		t.Parallel()
+
+		checker := &ICMPChecker{
+			name:        "BurstChecker",
+			address:     "127.0.0.1",
+			readTimeout: 50 * time.Millisecond,
+			protocol:    newMockProtocol(0, -1, 0, 0),
+			count:       4,
+			maxLoss:     25,
+		}
+
+		assert.NoError(t, checker.Check(context.Background()))
+		assert.Equal(t, 4, checker.lastStats.Sent)
+		assert.Equal(t, 3, checker.lastStats.Received)
+
+		details := checker.Details()
+		assert.Len(t, details, 1)
+		assert.Equal(t, "ping", details[0].Key)
+		assert.Contains(t, details[0].Value.String(), "4 sent, 3 received, 25% loss, rtt min/avg/max = ")
+	})
+
+	t.Run("Loss Exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		checker := &ICMPChecker{
+			name:        "BurstChecker",
+			address:     "127.0.0.1",
+			readTimeout: 50 * time.Millisecond,
+			protocol:    newMockProtocol(0, -1),
+			count:       4,
+			maxLoss:     25,
+		}
+
+		err := checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "packet loss of 50% exceeds 25%: 4 sent, 2 received, 50% loss")
+	})
+
+	t.Run("No Reply", func(t *testing.T) {
+		t.Parallel()
+
+		checker := &ICMPChecker{
+			name:        "BurstChecker",
+			address:     "127.0.0.1",
+			readTimeout: 50 * time.Millisecond,
+			protocol:    newMockProtocol(-1),
+			count:       3,
+			maxLoss:     50,
+		}
+
+		err := checker.Check(context.Background())
+		assert.EqualError(t, err, "no reply to 3 ICMP requests: failed to read ICMP reply: i/o timeout")
+	})
+
+	t.Run("Average RTT Exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		checker := &ICMPChecker{
+			name:        "BurstChecker",
+			address:     "127.0.0.1",
+			readTimeout: 50 * time.Millisecond,
+			protocol:    newMockProtocol(30 * time.Millisecond),
+			count:       2,
+			maxRTT:      10 * time.Millisecond,
+		}
+
+		err := checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "average round-trip time ")
+		assert.Contains(t, err.Error(), " exceeds 10ms: 2 sent, 2 received, 0% loss")
+	})
+
+	t.Run("Percentile RTT Within Limit", func(t *testing.T) {
+		t.Parallel()
+
+		checker := &ICMPChecker{
+			name:          "BurstChecker",
+			address:       "127.0.0.1",
+			readTimeout:   200 * time.Millisecond,
+			protocol:      newMockProtocol(0, 0, 0, 100*time.Millisecond),
+			count:         4,
+			maxRTT:        50 * time.Millisecond,
+			rttPercentile: 75,
+		}
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
 }
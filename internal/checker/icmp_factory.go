@@ -1,10 +1,10 @@
 package checker
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net"
-	"time"
 
 	"golang.org/x/net/icmp"
 	"golang.org/x/net/ipv4"
@@ -30,13 +30,16 @@ type Protocol interface {
 	// ListenPacket sets up a listener for ICMP packets on the specified network and address, using the provided context.
 	// Returns a net.PacketConn for reading and writing packets, or an error if the listener cannot be established.
 	ListenPacket(ctx context.Context, network, address string) (net.PacketConn, error)
-	// SetDeadline sets the read and write deadlines for the packet connection, affecting any I/O operations on the connection.
-	// Returns an error if setting the deadline fails.
-	SetDeadline(t time.Time) error
+	// Privileged reports whether the protocol uses raw ICMP sockets (true) or unprivileged datagram sockets (false).
+	Privileged() bool
+	// SetPrivileged switches between raw ICMP sockets and unprivileged datagram sockets.
+	// It must be called before ListenPacket.
+	SetPrivileged(privileged bool)
 }
 
-// newProtocol initializes a protocol based on the given address.
-func newProtocol(address string) (Protocol, error) {
+// newProtocol initializes a protocol based on the given address. Privileged protocols use raw ICMP sockets,
+// which require CAP_NET_RAW; unprivileged ones use datagram ICMP sockets allowed by "net.ipv4.ping_group_range".
+func newProtocol(address string, privileged bool, options packetOptions) (Protocol, error) {
 	ip := net.ParseIP(address)
 	if ip == nil {
 		// If the address is not an IP, try resolving it as a domain name
@@ -48,15 +51,72 @@ func newProtocol(address string) (Protocol, error) {
 	}
 
 	if ip.To16() != nil && ip.To4() == nil {
-		return &ICMPv6{}, nil
+		return &ICMPv6{privileged: privileged, options: options}, nil
 	}
 
-	return &ICMPv4{}, nil
+	return &ICMPv4{privileged: privileged, options: options}, nil
+}
+
+// datagramConn adapts an unprivileged ICMP socket so it accepts the *net.IPAddr destinations used with raw sockets.
+type datagramConn struct {
+	net.PacketConn
+}
+
+// WriteTo writes a packet to addr, converting an *net.IPAddr to the *net.UDPAddr expected by datagram sockets.
+func (c *datagramConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	if ipAddr, ok := addr.(*net.IPAddr); ok {
+		addr = &net.UDPAddr{IP: ipAddr.IP, Zone: ipAddr.Zone}
+	}
+	return c.PacketConn.WriteTo(b, addr)
+}
+
+// listenDatagram opens an unprivileged ICMP datagram socket on network ("udp4" or "udp6") with options applied.
+func listenDatagram(network, address, wildcard string, options packetOptions) (net.PacketConn, error) {
+	if address == "" {
+		address = wildcard
+	}
+	conn, err := openDatagramSocket(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for ICMP packets: %w", err)
+	}
+	if err := applyPacketOptions(conn, network == "udp6", options); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return &datagramConn{PacketConn: conn}, nil
+}
+
+// listenRaw opens a raw ICMP socket on network with options applied.
+func listenRaw(ctx context.Context, network, address string, isIPv6 bool, options packetOptions) (net.PacketConn, error) {
+	var lc net.ListenConfig
+	conn, err := lc.ListenPacket(ctx, network, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for ICMP packets: %w", err)
+	}
+	if err := applyPacketOptions(conn, isIPv6, options); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// validateEcho checks that an echo reply body matches the request's identifier, sequence number and payload.
+// Datagram sockets let the kernel rewrite the echo identifier, so it is only compared for raw sockets.
+func validateEcho(body icmp.MessageBody, identifier, sequence uint16, privileged bool, payload []byte) error {
+	echo, ok := body.(*icmp.Echo)
+	if !ok || (privileged && echo.ID != int(identifier)) || echo.Seq != int(sequence) {
+		return fmt.Errorf("identifier or sequence mismatch")
+	}
+	if !bytes.Equal(echo.Data, payload) {
+		return fmt.Errorf("echoed payload mismatch: got %d bytes, expected %d", len(echo.Data), len(payload))
+	}
+	return nil
 }
 
 // ICMPv4 implements the Protocol interface for IPv4 ICMP.
 type ICMPv4 struct {
-	conn net.PacketConn
+	privileged bool
+	options    packetOptions
 }
 
 // MakeRequest creates an ICMP echo request message.
@@ -64,7 +124,7 @@ func (p *ICMPv4) MakeRequest(identifier, sequence uint16) ([]byte, error) {
 	body := &icmp.Echo{
 		ID:   int(identifier),
 		Seq:  int(sequence),
-		Data: []byte("HELLO-R-U-THERE"),
+		Data: p.options.payload(),
 	}
 	msg := icmp.Message{
 		Type: ipv4.ICMPTypeEcho,
@@ -86,39 +146,42 @@ func (p *ICMPv4) ValidateReply(reply []byte, identifier, sequence uint16) error
 		return fmt.Errorf("unexpected ICMPv4 message type: %v", parsedMsg.Type)
 	}
 
-	body, ok := parsedMsg.Body.(*icmp.Echo)
-	if !ok || body.ID != int(identifier) || body.Seq != int(sequence) {
-		return fmt.Errorf("identifier or sequence mismatch")
-	}
-
-	return nil
+	return validateEcho(parsedMsg.Body, identifier, sequence, p.privileged, p.options.payload())
 }
 
 // Network returns the network type for the ICMP protocol.
-func (p *ICMPv4) Network() string { return "ip4:icmp" }
+func (p *ICMPv4) Network() string {
+	if p.privileged {
+		return "ip4:icmp"
+	}
+	return "udp4"
+}
+
+// Privileged reports whether raw ICMP sockets are used.
+func (p *ICMPv4) Privileged() bool { return p.privileged }
+
+// SetPrivileged switches between raw ICMP sockets and unprivileged datagram sockets.
+func (p *ICMPv4) SetPrivileged(privileged bool) { p.privileged = privileged }
 
 // ListenPacket creates a new ICMPv4 packet connection.
 func (p *ICMPv4) ListenPacket(ctx context.Context, network, address string) (net.PacketConn, error) {
-	var lc net.ListenConfig
-	conn, err := lc.ListenPacket(ctx, network, address)
+	var conn net.PacketConn
+	var err error
+	if p.privileged {
+		conn, err = listenRaw(ctx, network, address, false, p.options)
+	} else {
+		conn, err = listenDatagram(network, address, "0.0.0.0", p.options)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to listen for ICMP packets: %w", err)
+		return nil, err
 	}
-	p.conn = conn
 	return conn, nil
 }
 
-// SetDeadline sets the read and write deadlines associated with the connection. It is equivalent to calling both SetReadDeadline and SetWriteDeadline.
-func (p *ICMPv4) SetDeadline(t time.Time) error {
-	if p.conn == nil {
-		return fmt.Errorf("connection not initialized")
-	}
-	return p.conn.SetDeadline(t)
-}
-
 // ICMPv6 implements the Protocol interface for IPv6 ICMP.
 type ICMPv6 struct {
-	conn net.PacketConn
+	privileged bool
+	options    packetOptions
 }
 
 // MakeRequest creates an ICMP echo request message.
@@ -126,7 +189,7 @@ func (p *ICMPv6) MakeRequest(identifier, sequence uint16) ([]byte, error) {
 	body := &icmp.Echo{
 		ID:   int(identifier),
 		Seq:  int(sequence),
-		Data: []byte("HELLO-R-U-THERE"),
+		Data: p.options.payload(),
 	}
 	msg := icmp.Message{
 		Type: ipv6.ICMPTypeEchoRequest,
@@ -148,32 +211,34 @@ func (p *ICMPv6) ValidateReply(reply []byte, identifier, sequence uint16) error
 		return fmt.Errorf("unexpected ICMPv6 message type: %v", parsedMsg.Type)
 	}
 
-	body, ok := parsedMsg.Body.(*icmp.Echo)
-	if !ok || body.ID != int(identifier) || body.Seq != int(sequence) {
-		return fmt.Errorf("identifier or sequence mismatch")
-	}
-
-	return nil
+	return validateEcho(parsedMsg.Body, identifier, sequence, p.privileged, p.options.payload())
 }
 
 // Network returns the network type for the ICMP protocol.
-func (p *ICMPv6) Network() string { return "ip6:ipv6-icmp" }
+func (p *ICMPv6) Network() string {
+	if p.privileged {
+		return "ip6:ipv6-icmp"
+	}
+	return "udp6"
+}
+
+// Privileged reports whether raw ICMP sockets are used.
+func (p *ICMPv6) Privileged() bool { return p.privileged }
+
+// SetPrivileged switches between raw ICMP sockets and unprivileged datagram sockets.
+func (p *ICMPv6) SetPrivileged(privileged bool) { p.privileged = privileged }
 
 // ListenPacket creates a new ICMPv6 packet connection.
 func (p *ICMPv6) ListenPacket(ctx context.Context, network, address string) (net.PacketConn, error) {
-	var lc net.ListenConfig
-	conn, err := lc.ListenPacket(ctx, network, address)
-	if err != nil {
-		return nil, fmt.Errorf("failed to listen for ICMP packets: %w", err)
+	var conn net.PacketConn
+	var err error
+	if p.privileged {
+		conn, err = listenRaw(ctx, network, address, true, p.options)
+	} else {
+		conn, err = listenDatagram(network, address, "::", p.options)
 	}
-	p.conn = conn
-	return p.conn, nil
-}
-
-// SetDeadline sets the read and write deadlines associated with the connection. It is equivalent to calling both SetReadDeadline and SetWriteDeadline.
-func (p *ICMPv6) SetDeadline(t time.Time) error {
-	if p.conn == nil {
-		return fmt.Errorf("connection not initialized")
+	if err != nil {
+		return nil, err
 	}
-	return p.conn.SetDeadline(t)
+	return conn, nil
 }
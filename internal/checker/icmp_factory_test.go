@@ -2,6 +2,7 @@ package checker
 
 import (
 	"context"
+	"net"
 	"testing"
 	"time"
 
@@ -20,7 +21,7 @@ func TestNewProtocol(t *testing.T) {
 	t.Run("Valid IPv4 Address", func(t *testing.T) {
 		t.Parallel()
 
-		protocol, err := newProtocol("192.168.1.1")
+		protocol, err := newProtocol("192.168.1.1", true, packetOptions{})
 		assert.NoError(t, err)
 
 		if _, ok := protocol.(*ICMPv4); !ok {
@@ -31,7 +32,7 @@ func TestNewProtocol(t *testing.T) {
 	t.Run("Valid IPv6 Address", func(t *testing.T) {
 		t.Parallel()
 
-		protocol, err := newProtocol("2001:db8::1")
+		protocol, err := newProtocol("2001:db8::1", true, packetOptions{})
 		assert.NoError(t, err)
 
 		if _, ok := protocol.(*ICMPv6); !ok {
@@ -42,7 +43,7 @@ func TestNewProtocol(t *testing.T) {
 	t.Run("Unresolvable Address", func(t *testing.T) {
 		t.Parallel()
 
-		_, err := newProtocol("invalid.domain")
+		_, err := newProtocol("invalid.domain", true, packetOptions{})
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "invalid or unresolvable address: invalid.domain")
@@ -51,7 +52,7 @@ func TestNewProtocol(t *testing.T) {
 	t.Run("Unsupported IP Address", func(t *testing.T) {
 		t.Parallel()
 
-		_, err := newProtocol("300.300.300.300")
+		_, err := newProtocol("300.300.300.300", true, packetOptions{})
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "invalid or unresolvable address: 300.300.300.300")
@@ -64,7 +65,7 @@ func TestICMPv4MakeRequest(t *testing.T) {
 	t.Run("MakeRequest", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv4{}
+		protocol := &ICMPv4{privileged: true}
 		msg, err := protocol.MakeRequest(1234, 1)
 
 		assert.NoError(t, err)
@@ -78,41 +79,18 @@ func TestICMPv4_Network(t *testing.T) {
 	t.Run("Network", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv4{}
+		protocol := &ICMPv4{privileged: true}
 		assert.Equal(t, protocol.Network(), "ip4:icmp")
 	})
 }
 
-func TestICMPv4_SetDeadline(t *testing.T) {
-	t.Parallel()
-
-	t.Run("SetDeadline Success", func(t *testing.T) {
-		t.Parallel()
-
-		mockConn := testutils.MockPacketConn{}
-		protocol := &ICMPv4{conn: &mockConn}
-		err := protocol.SetDeadline(time.Now().Add(1 * time.Second))
-
-		assert.NoError(t, err)
-	})
-
-	t.Run("SetDeadline Error", func(t *testing.T) {
-		t.Parallel()
-
-		protocol := &ICMPv4{conn: nil}
-		err := protocol.SetDeadline(time.Now().Add(1 * time.Second))
-
-		assert.Error(t, err)
-	})
-}
-
 func TestICMPv4_ValidateReply(t *testing.T) {
 	t.Parallel()
 
 	t.Run("Unexpected Message Type", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv4{}
+		protocol := &ICMPv4{privileged: true}
 		request, _ := protocol.MakeRequest(1234, 1)
 
 		// Simulate a reply with a different identifier
@@ -127,7 +105,7 @@ func TestICMPv4_ValidateReply(t *testing.T) {
 	t.Run("ValidateReply Success", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv4{}
+		protocol := &ICMPv4{privileged: true}
 		request, _ := protocol.MakeRequest(1234, 1)
 
 		// Simulate a successful reply by modifying the request type to EchoReply
@@ -140,7 +118,7 @@ func TestICMPv4_ValidateReply(t *testing.T) {
 	t.Run("ValidateReply Identifier Mismatch", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv4{}
+		protocol := &ICMPv4{privileged: true}
 		request, _ := protocol.MakeRequest(1234, 1)
 
 		// Simulate a reply with a different identifier
@@ -156,7 +134,7 @@ func TestICMPv4_ValidateReply(t *testing.T) {
 	t.Run("Error Parsing Message", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv4{}
+		protocol := &ICMPv4{privileged: true}
 		// Pass an invalid byte slice that cannot be parsed as a valid ICMP message
 		reply := []byte{0xff, 0xff, 0xff}
 
@@ -168,7 +146,7 @@ func TestICMPv4_ValidateReply(t *testing.T) {
 	t.Run("Unexpected Message Type", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv4{}
+		protocol := &ICMPv4{privileged: true}
 		request, _ := protocol.MakeRequest(1234, 1)
 
 		// Simulate a reply with a different identifier
@@ -182,7 +160,7 @@ func TestICMPv4_ValidateReply(t *testing.T) {
 	t.Run("IdentifierOrSequenceMismatch", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv4{}
+		protocol := &ICMPv4{privileged: true}
 
 		// Create a valid ICMP echo request message
 		identifier := uint16(1234)
@@ -216,7 +194,7 @@ func TestICMPv4_ListenPacket(t *testing.T) {
 	t.Run("Successful ListenPacket", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv4{}
+		protocol := &ICMPv4{privileged: true}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 		defer cancel()
@@ -233,7 +211,7 @@ func TestICMPv4_ListenPacket(t *testing.T) {
 	t.Run("Invalid Network", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv4{}
+		protocol := &ICMPv4{privileged: true}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 		defer cancel()
@@ -246,7 +224,7 @@ func TestICMPv4_ListenPacket(t *testing.T) {
 	t.Run("Invalid Address", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv4{}
+		protocol := &ICMPv4{privileged: true}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 		defer cancel()
@@ -264,7 +242,7 @@ func TestICMPv6MakeRequest(t *testing.T) {
 	t.Run("MakeRequest", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv6{}
+		protocol := &ICMPv6{privileged: true}
 		msg, err := protocol.MakeRequest(1234, 1)
 
 		assert.NoError(t, err)
@@ -279,41 +257,18 @@ func TestICMPv6_Network(t *testing.T) {
 	t.Run("Network", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv6{}
+		protocol := &ICMPv6{privileged: true}
 		assert.Equal(t, protocol.Network(), "ip6:ipv6-icmp")
 	})
 }
 
-func TestICMPv6_SetDeadline(t *testing.T) {
-	t.Parallel()
-
-	t.Run("SetDeadline Success", func(t *testing.T) {
-		t.Parallel()
-
-		mockConn := testutils.MockPacketConn{}
-		protocol := &ICMPv6{conn: &mockConn}
-		err := protocol.SetDeadline(time.Now().Add(1 * time.Second))
-
-		assert.NoError(t, err)
-	})
-
-	t.Run("SetDeadline Error", func(t *testing.T) {
-		t.Parallel()
-
-		protocol := &ICMPv6{conn: nil}
-		err := protocol.SetDeadline(time.Now().Add(1 * time.Second))
-
-		assert.Error(t, err)
-	})
-}
-
 func TestICMPv6_ValidateReply(t *testing.T) {
 	t.Parallel()
 
 	t.Run("Unexpected Message Type", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv6{}
+		protocol := &ICMPv6{privileged: true}
 		request, _ := protocol.MakeRequest(1234, 1)
 
 		// Simulate a reply with a different identifier
@@ -328,7 +283,7 @@ func TestICMPv6_ValidateReply(t *testing.T) {
 	t.Run("ValidateReply Success", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv6{}
+		protocol := &ICMPv6{privileged: true}
 		request, _ := protocol.MakeRequest(1234, 1)
 
 		// Simulate a successful reply by modifying the request type to EchoReply
@@ -341,7 +296,7 @@ func TestICMPv6_ValidateReply(t *testing.T) {
 	t.Run("ValidateReply Identifier Mismatch", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv6{}
+		protocol := &ICMPv6{privileged: true}
 		request, _ := protocol.MakeRequest(1234, 1)
 
 		// Simulate a reply with a different identifier
@@ -357,7 +312,7 @@ func TestICMPv6_ValidateReply(t *testing.T) {
 	t.Run("Error Parsing Message", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv6{}
+		protocol := &ICMPv6{privileged: true}
 		// Pass an invalid byte slice that cannot be parsed as a valid ICMP message
 		reply := []byte{0xff, 0xff, 0xff}
 
@@ -369,7 +324,7 @@ func TestICMPv6_ValidateReply(t *testing.T) {
 	t.Run("Unexpected Message Type", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv6{}
+		protocol := &ICMPv6{privileged: true}
 		request, _ := protocol.MakeRequest(1234, 1)
 
 		// Simulate a reply with a different identifier
@@ -384,7 +339,7 @@ func TestICMPv6_ValidateReply(t *testing.T) {
 	t.Run("IdentifierOrSequenceMismatch", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv6{}
+		protocol := &ICMPv6{privileged: true}
 
 		// Create a valid ICMP echo request message
 		identifier := uint16(1234)
@@ -419,7 +374,7 @@ func TestICMPv6_ListenPacket(t *testing.T) {
 	t.Run("Successful ListenPacket", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv6{}
+		protocol := &ICMPv6{privileged: true}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 		defer cancel()
@@ -436,7 +391,7 @@ func TestICMPv6_ListenPacket(t *testing.T) {
 	t.Run("Invalid Network", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv6{}
+		protocol := &ICMPv6{privileged: true}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 		defer cancel()
@@ -450,7 +405,7 @@ func TestICMPv6_ListenPacket(t *testing.T) {
 	t.Run("Invalid Address", func(t *testing.T) {
 		t.Parallel()
 
-		protocol := &ICMPv6{}
+		protocol := &ICMPv6{privileged: true}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 		defer cancel()
@@ -461,3 +416,76 @@ func TestICMPv6_ListenPacket(t *testing.T) {
 		assert.EqualError(t, err, "failed to listen for ICMP packets: listen ip6:ipv6-icmp: lookup invalid-address: no such host")
 	})
 }
+
+func TestICMP_Unprivileged(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NewProtocol Unprivileged", func(t *testing.T) {
+		t.Parallel()
+
+		protocol, err := newProtocol("127.0.0.1", false, packetOptions{})
+		assert.NoError(t, err)
+		assert.False(t, protocol.Privileged())
+		assert.Equal(t, "udp4", protocol.Network())
+	})
+
+	t.Run("Network", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "udp4", (&ICMPv4{}).Network())
+		assert.Equal(t, "udp6", (&ICMPv6{}).Network())
+	})
+
+	t.Run("SetPrivileged", func(t *testing.T) {
+		t.Parallel()
+
+		protocol := &ICMPv4{}
+		protocol.SetPrivileged(true)
+		assert.True(t, protocol.Privileged())
+		assert.Equal(t, "ip4:icmp", protocol.Network())
+	})
+
+	t.Run("ICMPv4 ValidateReply Ignores Identifier", func(t *testing.T) {
+		t.Parallel()
+
+		protocol := &ICMPv4{}
+		reply, err := (&icmp.Message{
+			Type: ipv4.ICMPTypeEchoReply,
+			Body: &icmp.Echo{ID: 4321, Seq: 1, Data: []byte("HELLO-R-U-THERE")},
+		}).Marshal(nil)
+		assert.NoError(t, err)
+
+		assert.NoError(t, protocol.ValidateReply(reply, 1234, 1))
+		assert.EqualError(t, protocol.ValidateReply(reply, 1234, 2), "identifier or sequence mismatch")
+	})
+
+	t.Run("ICMPv6 ValidateReply Ignores Identifier", func(t *testing.T) {
+		t.Parallel()
+
+		protocol := &ICMPv6{}
+		reply, err := (&icmp.Message{
+			Type: ipv6.ICMPTypeEchoReply,
+			Body: &icmp.Echo{ID: 4321, Seq: 1, Data: []byte("HELLO-R-U-THERE")},
+		}).Marshal(nil)
+		assert.NoError(t, err)
+
+		assert.NoError(t, protocol.ValidateReply(reply, 1234, 1))
+		assert.EqualError(t, protocol.ValidateReply(reply, 1234, 2), "identifier or sequence mismatch")
+	})
+
+	t.Run("Datagram Conn Converts Address", func(t *testing.T) {
+		t.Parallel()
+
+		var written net.Addr
+		conn := &datagramConn{PacketConn: &testutils.MockPacketConn{
+			WriteToFunc: func(b []byte, addr net.Addr) (int, error) {
+				written = addr
+				return len(b), nil
+			},
+		}}
+
+		_, err := conn.WriteTo([]byte{}, &net.IPAddr{IP: net.ParseIP("127.0.0.1")})
+		assert.NoError(t, err)
+		assert.Equal(t, &net.UDPAddr{IP: net.ParseIP("127.0.0.1")}, written)
+	})
+}
@@ -0,0 +1,213 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// icmpSequence hands out sequence numbers that are unique across all ICMP checks of the process,
+// so replies read from a shared listener can be told apart although every check uses the same identifier.
+var icmpSequence atomic.Uint32
+
+// nextICMPSequence returns the next ICMP echo sequence number.
+func nextICMPSequence() uint16 {
+	return uint16(icmpSequence.Add(1) & 0xffff)
+}
+
+const (
+	maxICMPPacketSize   int = 65535 // Largest ICMP message that fits into an IP packet
+	icmpWaiterQueueSize int = 4     // Replies queued per waiter, e.g. duplicates or replies to other processes with the same sequence number
+)
+
+// defaultListenerPool is shared by all ICMP checkers created with NewChecker.
+var defaultListenerPool = newListenerPool()
+
+// listenerPool shares one ICMP listener per network between concurrent checks. Every raw ICMP socket
+// receives all ICMP packets of the host, so separate sockets per check would read each other's replies.
+type listenerPool struct {
+	mu        sync.Mutex
+	listeners map[string]*icmpListener
+}
+
+// newListenerPool creates an empty listenerPool.
+func newListenerPool() *listenerPool {
+	return &listenerPool{listeners: make(map[string]*icmpListener)}
+}
+
+// acquire returns the listener for network, opening it with open if there is none yet.
+// Every successful acquire must be paired with a release.
+func (p *listenerPool) acquire(network string, open func() (net.PacketConn, error)) (*icmpListener, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if l, ok := p.listeners[network]; ok {
+		l.refs++
+		return l, nil
+	}
+
+	conn, err := open()
+	if err != nil {
+		return nil, err
+	}
+
+	l := &icmpListener{
+		pool:    p,
+		network: network,
+		conn:    conn,
+		refs:    1,
+		waiters: make(map[uint16]*icmpWaiter),
+		done:    make(chan struct{}),
+	}
+	p.listeners[network] = l
+	go l.read()
+
+	return l, nil
+}
+
+// release drops a reference to l and closes its connection once no check uses it anymore.
+func (p *listenerPool) release(l *icmpListener) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	l.refs--
+	if l.refs > 0 {
+		return
+	}
+	p.remove(l)
+	_ = l.conn.Close()
+}
+
+// remove drops l from the pool so the next acquire opens a new listener. The caller must hold p.mu.
+func (p *listenerPool) remove(l *icmpListener) {
+	if p.listeners[l.network] == l {
+		delete(p.listeners, l.network)
+	}
+}
+
+// icmpReply is a packet read by an icmpListener.
+type icmpReply struct {
+	data     []byte
+	received time.Time
+}
+
+// icmpWaiter receives the echo replies carrying the sequence number of a check's request.
+type icmpWaiter struct {
+	seq     uint16
+	replies chan icmpReply
+}
+
+// icmpListener reads ICMP packets from a shared connection and hands each echo reply to the check waiting for
+// its sequence number. Sequence numbers are unique across checks, while the identifier is the same for all of them
+// and may even be rewritten by the kernel for datagram sockets.
+type icmpListener struct {
+	pool    *listenerPool
+	network string
+	conn    net.PacketConn
+	refs    int // Guarded by pool.mu
+
+	writeMu sync.Mutex // Serializes writes, so the deadline of one check cannot affect the write of another
+
+	mu      sync.Mutex
+	waiters map[uint16]*icmpWaiter // By sequence number
+	err     error                  // Read error that stopped the listener
+	done    chan struct{}          // Closed once err is set
+}
+
+// register adds a waiter for the echo replies with sequence number seq. It fails if the listener already stopped.
+func (l *icmpListener) register(seq uint16) (*icmpWaiter, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.err != nil {
+		return nil, l.err
+	}
+	if _, ok := l.waiters[seq]; ok {
+		return nil, fmt.Errorf("sequence number %d is already in use", seq)
+	}
+
+	w := &icmpWaiter{seq: seq, replies: make(chan icmpReply, icmpWaiterQueueSize)}
+	l.waiters[seq] = w
+	return w, nil
+}
+
+// unregister removes a waiter added with register.
+func (l *icmpListener) unregister(w *icmpWaiter) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.waiters[w.seq] == w {
+		delete(l.waiters, w.seq)
+	}
+}
+
+// write sends b to dst, failing if the write does not complete within timeout.
+func (l *icmpListener) write(b []byte, dst net.Addr, timeout time.Duration) error {
+	l.writeMu.Lock()
+	defer l.writeMu.Unlock()
+
+	if err := l.conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return fmt.Errorf("failed to set write deadline: %w", err)
+	}
+	if _, err := l.conn.WriteTo(b, dst); err != nil {
+		return fmt.Errorf("failed to send ICMP request: %w", err)
+	}
+	return nil
+}
+
+// read dispatches echo replies to the waiter registered for their sequence number until reading fails, e.g. because
+// the connection was closed. Other packets, replies nobody waits for and replies to waiters that are not keeping up
+// are dropped.
+func (l *icmpListener) read() {
+	buf := make([]byte, maxICMPPacketSize)
+	for {
+		n, _, err := l.conn.ReadFrom(buf)
+		if err != nil {
+			l.stop(err)
+			return
+		}
+		received := time.Now()
+
+		seq, ok := echoReplySequence(buf[:n])
+		if !ok {
+			continue
+		}
+
+		l.mu.Lock()
+		w := l.waiters[seq]
+		l.mu.Unlock()
+		if w == nil {
+			continue
+		}
+
+		select {
+		case w.replies <- icmpReply{data: bytes.Clone(buf[:n]), received: received}:
+		default:
+		}
+	}
+}
+
+// echoReplySequence returns the sequence number of an ICMPv4 or ICMPv6 echo reply. The echo reply types are 0 for
+// ICMPv4 and 129 for ICMPv6, neither is used by the other protocol, so the listener does not need to know its family.
+func echoReplySequence(packet []byte) (uint16, bool) {
+	if len(packet) < 8 || (packet[0] != 0 && packet[0] != 129) || packet[1] != 0 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint16(packet[6:8]), true
+}
+
+// stop records the read error, wakes up all waiters and removes the listener from its pool.
+func (l *icmpListener) stop(err error) {
+	l.mu.Lock()
+	l.err = err
+	close(l.done)
+	l.mu.Unlock()
+
+	l.pool.mu.Lock()
+	l.pool.remove(l)
+	l.pool.mu.Unlock()
+}
@@ -0,0 +1,320 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containeroo/portpatrol/internal/testutils"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+// newEchoConn returns a connection that answers every echo request with an unrelated echo reply
+// followed by the matching one, like a raw socket shared with other pings.
+func newEchoConn() *testutils.MockPacketConn {
+	replies := make(chan []byte, 64)
+	closed := make(chan struct{})
+	var closeOnce sync.Once
+
+	return &testutils.MockPacketConn{
+		WriteToFunc: func(b []byte, addr net.Addr) (int, error) {
+			msg, err := icmp.ParseMessage(icmpv4ProtocolNumber, b)
+			if err != nil {
+				return 0, err
+			}
+			echo := msg.Body.(*icmp.Echo)
+
+			for _, seq := range []int{echo.Seq + 1000, echo.Seq} {
+				reply, _ := (&icmp.Message{
+					Type: ipv4.ICMPTypeEchoReply,
+					Body: &icmp.Echo{ID: echo.ID, Seq: seq, Data: echo.Data},
+				}).Marshal(nil)
+				replies <- reply
+			}
+			return len(b), nil
+		},
+		ReadFromFunc: func(b []byte) (int, net.Addr, error) {
+			select {
+			case reply := <-replies:
+				return copy(b, reply), &net.IPAddr{}, nil
+			case <-closed:
+				return 0, nil, net.ErrClosed
+			}
+		},
+		CloseFunc: func() error {
+			closeOnce.Do(func() { close(closed) })
+			return nil
+		},
+	}
+}
+
+func TestNextICMPSequence(t *testing.T) {
+	t.Parallel()
+
+	first := nextICMPSequence()
+	second := nextICMPSequence()
+	assert.NotEqual(t, first, second)
+}
+
+func TestListenerPool(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Shares Listener Per Network", func(t *testing.T) {
+		t.Parallel()
+
+		pool := newListenerPool()
+		opened := 0
+		closed := 0
+		open := func() (net.PacketConn, error) {
+			opened++
+			return &testutils.MockPacketConn{
+				CloseFunc: func() error {
+					closed++
+					return nil
+				},
+			}, nil
+		}
+
+		first, err := pool.acquire("ip4:icmp", open)
+		assert.NoError(t, err)
+		second, err := pool.acquire("ip4:icmp", open)
+		assert.NoError(t, err)
+		other, err := pool.acquire("udp4", open)
+		assert.NoError(t, err)
+
+		assert.Same(t, first, second)
+		assert.NotSame(t, first, other)
+		assert.Equal(t, 2, opened)
+
+		pool.release(first)
+		assert.Equal(t, 0, closed)
+		pool.release(second)
+		assert.Equal(t, 1, closed)
+		pool.release(other)
+		assert.Equal(t, 2, closed)
+		assert.Empty(t, pool.listeners)
+	})
+
+	t.Run("Open Error", func(t *testing.T) {
+		t.Parallel()
+
+		pool := newListenerPool()
+		_, err := pool.acquire("ip4:icmp", func() (net.PacketConn, error) {
+			return nil, errors.New("mock listen error")
+		})
+
+		assert.EqualError(t, err, "mock listen error")
+		assert.Empty(t, pool.listeners)
+	})
+
+	t.Run("Read Error Stops Listener", func(t *testing.T) {
+		t.Parallel()
+
+		pool := newListenerPool()
+		listener, err := pool.acquire("ip4:icmp", func() (net.PacketConn, error) {
+			return &testutils.MockPacketConn{
+				ReadFromFunc: func(b []byte) (int, net.Addr, error) {
+					return 0, nil, errors.New("mock read error")
+				},
+			}, nil
+		})
+		assert.NoError(t, err)
+		defer pool.release(listener)
+
+		<-listener.done
+		_, err = listener.register(1)
+		assert.EqualError(t, err, "mock read error")
+
+		pool.mu.Lock()
+		defer pool.mu.Unlock()
+		assert.Empty(t, pool.listeners)
+	})
+}
+
+func TestICMPListenerDispatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Each Waiter Gets Its Own Reply", func(t *testing.T) {
+		t.Parallel()
+
+		const waiters = 100
+		packets := make(chan []byte, 4*waiters)
+		closed := make(chan struct{})
+		pool := newListenerPool()
+		listener, err := pool.acquire("ip4:icmp", func() (net.PacketConn, error) {
+			return &testutils.MockPacketConn{
+				ReadFromFunc: func(b []byte) (int, net.Addr, error) {
+					select {
+					case packet := <-packets:
+						return copy(b, packet), &net.IPAddr{}, nil
+					case <-closed:
+						return 0, nil, net.ErrClosed
+					}
+				},
+				CloseFunc: func() error {
+					close(closed)
+					return nil
+				},
+			}, nil
+		})
+		assert.NoError(t, err)
+		defer pool.release(listener)
+
+		marshal := func(typ icmp.Type, seq int) []byte {
+			packet, _ := (&icmp.Message{Type: typ, Body: &icmp.Echo{ID: 7, Seq: seq}}).Marshal(nil)
+			return packet
+		}
+
+		registered := make([]*icmpWaiter, waiters)
+		for i := range registered {
+			registered[i], err = listener.register(uint16(i + 1))
+			assert.NoError(t, err)
+		}
+
+		// Unrelated replies and echo requests of the waiters' sequence numbers arrive first, more than a
+		// waiter could queue, followed by the replies in reverse order.
+		for i := range waiters {
+			packets <- marshal(ipv4.ICMPTypeEchoReply, 1000+i)
+			packets <- marshal(ipv4.ICMPTypeEcho, i+1)
+		}
+		for i := waiters; i > 0; i-- {
+			packets <- marshal(ipv4.ICMPTypeEchoReply, i)
+		}
+
+		var wg sync.WaitGroup
+		for _, w := range registered {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer listener.unregister(w)
+
+				select {
+				case reply := <-w.replies:
+					seq, ok := echoReplySequence(reply.data)
+					assert.True(t, ok)
+					assert.Equal(t, w.seq, seq)
+				case <-time.After(time.Second):
+					t.Errorf("no reply for sequence number %d", w.seq)
+				}
+			}()
+		}
+		wg.Wait()
+
+		for _, w := range registered {
+			assert.Empty(t, w.replies, "sequence number %d", w.seq)
+		}
+	})
+
+	t.Run("Sequence Number In Use", func(t *testing.T) {
+		t.Parallel()
+
+		pool := newListenerPool()
+		listener, err := pool.acquire("ip4:icmp", func() (net.PacketConn, error) { return &testutils.MockPacketConn{}, nil })
+		assert.NoError(t, err)
+		defer pool.release(listener)
+
+		w, err := listener.register(42)
+		assert.NoError(t, err)
+		_, err = listener.register(42)
+		assert.EqualError(t, err, "sequence number 42 is already in use")
+
+		listener.unregister(w)
+		_, err = listener.register(42)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Writes Keep Their Own Deadline", func(t *testing.T) {
+		t.Parallel()
+
+		var deadline time.Time
+		pool := newListenerPool()
+		listener, err := pool.acquire("ip4:icmp", func() (net.PacketConn, error) {
+			return &testutils.MockPacketConn{
+				SetWriteDeadlineFunc: func(d time.Time) error {
+					deadline = d
+					return nil
+				},
+				WriteToFunc: func(b []byte, addr net.Addr) (int, error) {
+					expected := deadline
+					time.Sleep(time.Millisecond) // Give other writes the chance to overwrite the deadline
+					if !deadline.Equal(expected) {
+						return 0, errors.New("deadline changed during write")
+					}
+					return len(b), nil
+				},
+			}, nil
+		})
+		assert.NoError(t, err)
+		defer pool.release(listener)
+
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, listener.write([]byte{8}, &net.IPAddr{}, time.Duration(i+1)*time.Second))
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestICMPCheckerSharedListener(t *testing.T) {
+	t.Parallel()
+
+	pool := newListenerPool()
+	var mu sync.Mutex
+	opened := 0
+
+	conn := newEchoConn()
+	newChecker := func() *ICMPChecker {
+		protocol := &ICMPv4{privileged: true}
+		return &ICMPChecker{
+			name:        "SharedChecker",
+			address:     "127.0.0.1",
+			readTimeout: time.Second,
+			count:       3,
+			listeners:   pool,
+			protocol: &testutils.MockProtocol{
+				MakeRequestFunc:   protocol.MakeRequest,
+				ValidateReplyFunc: protocol.ValidateReply,
+				NetworkFunc:       protocol.Network,
+				ListenPacketFunc: func(ctx context.Context, network, address string) (net.PacketConn, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					opened++
+					return conn, nil
+				},
+			},
+		}
+	}
+
+	// Hold a reference, so all checks share the same listener.
+	listener, err := pool.acquire(packetOptions{}.listenerKey("ip4:icmp"), func() (net.PacketConn, error) { return conn, nil })
+	assert.NoError(t, err)
+	defer pool.release(listener)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- newChecker().Check(context.Background())
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 0, opened)
+}
@@ -0,0 +1,77 @@
+package checker
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// defaultICMPPayload is the payload of echo requests without a configured size.
+const defaultICMPPayload string = "HELLO-R-U-THERE"
+
+// packetOptions configures the echo requests sent by a Protocol.
+type packetOptions struct {
+	size         int  // Payload size in bytes, zero uses the default payload
+	ttl          int  // Time to live (hop limit for IPv6), zero uses the system default
+	dontFragment bool // Refuse to fragment requests, for path MTU checks
+}
+
+// payload returns the echo request payload: the default payload repeated up to the configured size.
+func (o packetOptions) payload() []byte {
+	if o.size == 0 {
+		return []byte(defaultICMPPayload)
+	}
+	return bytes.Repeat([]byte(defaultICMPPayload), o.size/len(defaultICMPPayload)+1)[:o.size]
+}
+
+// listenerKey returns the key of the shared listener for network. Connections with different
+// socket options cannot be shared, so the options are part of the key.
+func (o packetOptions) listenerKey(network string) string {
+	return fmt.Sprintf("%s ttl=%d df=%t", network, o.ttl, o.dontFragment)
+}
+
+// applyPacketOptions sets the TTL and don't-fragment socket options of an ICMP connection.
+func applyPacketOptions(conn net.PacketConn, isIPv6 bool, opts packetOptions) error {
+	if opts.ttl > 0 {
+		if err := setTTL(conn, isIPv6, opts.ttl); err != nil {
+			return fmt.Errorf("failed to set TTL: %w", err)
+		}
+	}
+
+	if opts.dontFragment {
+		sc, ok := conn.(syscall.Conn)
+		if !ok {
+			return errors.New("failed to set don't fragment: not supported by the connection")
+		}
+		rc, err := sc.SyscallConn()
+		if err != nil {
+			return fmt.Errorf("failed to set don't fragment: %w", err)
+		}
+		if err := setDontFragment(rc, isIPv6); err != nil {
+			return fmt.Errorf("failed to set don't fragment: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// setTTL sets the time to live of IPv4 or the hop limit of IPv6 packets sent on conn.
+func setTTL(conn net.PacketConn, isIPv6 bool, ttl int) error {
+	if icmpConn, ok := conn.(*icmp.PacketConn); ok {
+		if isIPv6 {
+			return icmpConn.IPv6PacketConn().SetHopLimit(ttl)
+		}
+		return icmpConn.IPv4PacketConn().SetTTL(ttl)
+	}
+
+	if isIPv6 {
+		return ipv6.NewPacketConn(conn).SetHopLimit(ttl)
+	}
+	return ipv4.NewPacketConn(conn).SetTTL(ttl)
+}
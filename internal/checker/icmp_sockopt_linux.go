@@ -0,0 +1,66 @@
+//go:build linux
+
+package checker
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// setDontFragment sets the don't-fragment bit on packets sent on rc and makes the kernel
+// refuse packets larger than the path MTU instead of fragmenting them.
+func setDontFragment(rc syscall.RawConn, isIPv6 bool) error {
+	level, name, value := syscall.IPPROTO_IP, syscall.IP_MTU_DISCOVER, syscall.IP_PMTUDISC_DO
+	if isIPv6 {
+		level, name, value = syscall.IPPROTO_IPV6, syscall.IPV6_MTU_DISCOVER, syscall.IPV6_PMTUDISC_DO
+	}
+
+	var sockErr error
+	if err := rc.Control(func(fd uintptr) {
+		sockErr = syscall.SetsockoptInt(int(fd), level, name, value)
+	}); err != nil {
+		return err
+	}
+	return os.NewSyscallError("setsockopt", sockErr)
+}
+
+// openDatagramSocket opens an unprivileged ICMP datagram socket ("ping socket") on network ("udp4" or "udp6")
+// bound to address. Unlike icmp.ListenPacket, the returned connection exposes its file descriptor,
+// so socket options like don't-fragment can be applied.
+func openDatagramSocket(network, address string) (net.PacketConn, error) {
+	family, proto := syscall.AF_INET, icmpv4ProtocolNumber
+	if network == "udp6" {
+		family, proto = syscall.AF_INET6, icmpv6ProtocolNumber
+	}
+
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil, &net.AddrError{Err: "invalid IP address", Addr: address}
+	}
+
+	var sa syscall.Sockaddr
+	if family == syscall.AF_INET {
+		addr := &syscall.SockaddrInet4{}
+		copy(addr.Addr[:], ip.To4())
+		sa = addr
+	} else {
+		addr := &syscall.SockaddrInet6{}
+		copy(addr.Addr[:], ip.To16())
+		sa = addr
+	}
+
+	fd, err := syscall.Socket(family, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, proto)
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+	if err := syscall.Bind(fd, sa); err != nil {
+		_ = syscall.Close(fd)
+		return nil, os.NewSyscallError("bind", err)
+	}
+
+	f := os.NewFile(uintptr(fd), "datagram-oriented icmp")
+	defer f.Close()
+
+	return net.FilePacketConn(f)
+}
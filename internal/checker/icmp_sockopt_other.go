@@ -0,0 +1,21 @@
+//go:build !linux
+
+package checker
+
+import (
+	"errors"
+	"net"
+	"syscall"
+
+	"golang.org/x/net/icmp"
+)
+
+// setDontFragment is only implemented on Linux.
+func setDontFragment(rc syscall.RawConn, isIPv6 bool) error {
+	return errors.New("not supported on this platform")
+}
+
+// openDatagramSocket opens an unprivileged ICMP datagram socket on network ("udp4" or "udp6") bound to address.
+func openDatagramSocket(network, address string) (net.PacketConn, error) {
+	return icmp.ListenPacket(network, address)
+}
@@ -0,0 +1,119 @@
+package checker
+
+import (
+	"net"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containeroo/portpatrol/internal/testutils"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+func TestPacketOptionsPayload(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Default Payload", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, []byte("HELLO-R-U-THERE"), packetOptions{}.payload())
+	})
+
+	t.Run("Sized Payload", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, []byte("HELLO"), packetOptions{size: 5}.payload())
+		assert.Equal(t, []byte("HELLO-R-U-THEREHELLO"), packetOptions{size: 20}.payload())
+		assert.Len(t, packetOptions{size: 1400}.payload(), 1400)
+	})
+
+	t.Run("Listener Key", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "ip4:icmp ttl=0 df=false", packetOptions{size: 1400}.listenerKey("ip4:icmp"))
+		assert.Equal(t, "udp4 ttl=3 df=true", packetOptions{ttl: 3, dontFragment: true}.listenerKey("udp4"))
+	})
+}
+
+func TestICMPSizedRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("MakeRequest", func(t *testing.T) {
+		t.Parallel()
+
+		protocol := &ICMPv4{privileged: true, options: packetOptions{size: 1400}}
+		msg, err := protocol.MakeRequest(1234, 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, msg, 1408)
+	})
+
+	t.Run("ValidateReply Payload Mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		protocol := &ICMPv4{privileged: true, options: packetOptions{size: 1400}}
+		reply, err := (&icmp.Message{
+			Type: ipv4.ICMPTypeEchoReply,
+			Body: &icmp.Echo{ID: 1234, Seq: 1, Data: packetOptions{size: 576}.payload()},
+		}).Marshal(nil)
+		assert.NoError(t, err)
+
+		err = protocol.ValidateReply(reply, 1234, 1)
+		assert.EqualError(t, err, "echoed payload mismatch: got 576 bytes, expected 1400")
+	})
+
+	t.Run("ValidateReply Sized Payload", func(t *testing.T) {
+		t.Parallel()
+
+		protocol := &ICMPv6{privileged: true, options: packetOptions{size: 1400}}
+		request, err := protocol.MakeRequest(1234, 1)
+		assert.NoError(t, err)
+
+		request[0] = 129 // Turn the echo request into an echo reply
+		assert.NoError(t, protocol.ValidateReply(request, 1234, 1))
+	})
+}
+
+func TestApplyPacketOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("TTL", func(t *testing.T) {
+		t.Parallel()
+
+		conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		err = applyPacketOptions(conn, false, packetOptions{ttl: 7})
+		assert.NoError(t, err)
+
+		ttl, err := ipv4.NewPacketConn(conn).TTL()
+		assert.NoError(t, err)
+		assert.Equal(t, 7, ttl)
+	})
+
+	t.Run("Dont Fragment", func(t *testing.T) {
+		t.Parallel()
+
+		if runtime.GOOS != "linux" {
+			t.Skip("don't fragment is only supported on Linux")
+		}
+
+		conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		err = applyPacketOptions(conn, false, packetOptions{dontFragment: true})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Dont Fragment Unsupported Connection", func(t *testing.T) {
+		t.Parallel()
+
+		err := applyPacketOptions(&testutils.MockPacketConn{}, false, packetOptions{dontFragment: true})
+		assert.EqualError(t, err, "failed to set don't fragment: not supported by the connection")
+	})
+}
@@ -0,0 +1,73 @@
+package checker
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// PingStats summarizes a burst of ICMP echo requests, like the summary printed by ping.
+type PingStats struct {
+	Sent     int
+	Received int
+	RTTs     []time.Duration // Round-trip times of the received replies
+}
+
+// Loss returns the percentage of echo requests without a valid reply.
+func (s PingStats) Loss() float64 {
+	if s.Sent == 0 {
+		return 0
+	}
+	return float64(s.Sent-s.Received) / float64(s.Sent) * 100
+}
+
+// Min returns the shortest round-trip time, or zero if no reply was received.
+func (s PingStats) Min() time.Duration {
+	if len(s.RTTs) == 0 {
+		return 0
+	}
+	return slices.Min(s.RTTs)
+}
+
+// Max returns the longest round-trip time, or zero if no reply was received.
+func (s PingStats) Max() time.Duration {
+	if len(s.RTTs) == 0 {
+		return 0
+	}
+	return slices.Max(s.RTTs)
+}
+
+// Avg returns the average round-trip time, or zero if no reply was received.
+func (s PingStats) Avg() time.Duration {
+	if len(s.RTTs) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, rtt := range s.RTTs {
+		total += rtt
+	}
+	return total / time.Duration(len(s.RTTs))
+}
+
+// Percentile returns the nearest-rank percentile (1-100) of the round-trip times, or zero if no reply was received.
+func (s PingStats) Percentile(percentile int) time.Duration {
+	if len(s.RTTs) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(s.RTTs)
+	slices.Sort(sorted)
+
+	rank := int(math.Ceil(float64(percentile) / 100 * float64(len(sorted))))
+	rank = min(max(rank, 1), len(sorted))
+	return sorted[rank-1]
+}
+
+// String returns a summary like "5 sent, 4 received, 20% loss, rtt min/avg/max = 1.1ms/1.5ms/2ms".
+func (s PingStats) String() string {
+	summary := fmt.Sprintf("%d sent, %d received, %.0f%% loss", s.Sent, s.Received, s.Loss())
+	if len(s.RTTs) == 0 {
+		return summary
+	}
+	return fmt.Sprintf("%s, rtt min/avg/max = %s/%s/%s", summary, s.Min(), s.Avg(), s.Max())
+}
@@ -0,0 +1,50 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPingStats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Summary", func(t *testing.T) {
+		t.Parallel()
+
+		stats := PingStats{
+			Sent:     5,
+			Received: 4,
+			RTTs:     []time.Duration{4 * time.Millisecond, 1 * time.Millisecond, 3 * time.Millisecond, 2 * time.Millisecond},
+		}
+
+		assert.Equal(t, 20.0, stats.Loss())
+		assert.Equal(t, 1*time.Millisecond, stats.Min())
+		assert.Equal(t, 2500*time.Microsecond, stats.Avg())
+		assert.Equal(t, 4*time.Millisecond, stats.Max())
+		assert.Equal(t, "5 sent, 4 received, 20% loss, rtt min/avg/max = 1ms/2.5ms/4ms", stats.String())
+	})
+
+	t.Run("Percentile", func(t *testing.T) {
+		t.Parallel()
+
+		stats := PingStats{Sent: 4, Received: 4, RTTs: []time.Duration{40, 10, 30, 20}}
+
+		assert.Equal(t, time.Duration(10), stats.Percentile(1))
+		assert.Equal(t, time.Duration(20), stats.Percentile(50))
+		assert.Equal(t, time.Duration(30), stats.Percentile(75))
+		assert.Equal(t, time.Duration(40), stats.Percentile(100))
+	})
+
+	t.Run("No Replies", func(t *testing.T) {
+		t.Parallel()
+
+		stats := PingStats{Sent: 3}
+
+		assert.Equal(t, 100.0, stats.Loss())
+		assert.Zero(t, stats.Avg())
+		assert.Zero(t, stats.Percentile(90))
+		assert.Equal(t, "3 sent, 0 received, 100% loss", stats.String())
+	})
+}
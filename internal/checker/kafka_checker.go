@@ -0,0 +1,522 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"time"
+)
+
+const (
+	defaultKafkaTimeout  time.Duration = 2 * time.Second
+	defaultKafkaClientID string        = "portpatrol"
+	maxKafkaResponseSize int           = 16 * 1024 * 1024 // Largest response accepted
+)
+
+// Kafka API keys of the requests sent by the KafkaChecker.
+const (
+	kafkaMetadata         int16 = 3
+	kafkaSaslHandshake    int16 = 17
+	kafkaAPIVersions      int16 = 18
+	kafkaSaslAuthenticate int16 = 36
+)
+
+// Metadata request versions supported by the KafkaChecker. Version 4 was added in Kafka 1.0 and
+// version 9 introduced the flexible encoding, which is not implemented.
+const (
+	minKafkaMetadataVersion int16 = 4
+	maxKafkaMetadataVersion int16 = 8
+)
+
+// kafkaErrorNames holds the names of the error codes reported by the KafkaChecker.
+var kafkaErrorNames = map[int16]string{
+	3:  "UNKNOWN_TOPIC_OR_PARTITION",
+	5:  "LEADER_NOT_AVAILABLE",
+	6:  "NOT_LEADER_OR_FOLLOWER",
+	15: "COORDINATOR_NOT_AVAILABLE",
+	29: "TOPIC_AUTHORIZATION_FAILED",
+	31: "CLUSTER_AUTHORIZATION_FAILED",
+	33: "UNSUPPORTED_SASL_MECHANISM",
+	34: "ILLEGAL_SASL_STATE",
+	35: "UNSUPPORTED_VERSION",
+	58: "SASL_AUTHENTICATION_FAILED",
+}
+
+// kafkaError formats a Kafka error code with its name.
+func kafkaError(code int16) string {
+	if name, ok := kafkaErrorNames[code]; ok {
+		return fmt.Sprintf("%s (%d)", name, code)
+	}
+	return fmt.Sprintf("error code %d", code)
+}
+
+// KafkaChecker implements the Checker interface by sending ApiVersions and Metadata requests to a broker.
+type KafkaChecker struct {
+	name          string
+	address       string
+	timeout       time.Duration // Timeout for connecting and all requests
+	topic         string        // Topic that must exist with a leader for every partition, not checked if empty
+	username      string        // Authenticates with SASL/PLAIN if set
+	password      string
+	tlsConfig     *tls.Config
+	correlationID int32
+	lastMetadata  *kafkaMetadataResponse
+}
+
+func (c *KafkaChecker) Address() string { return c.address }
+func (c *KafkaChecker) Name() string    { return c.name }
+func (c *KafkaChecker) Type() string    { return Kafka.String() }
+
+// Details returns the number of brokers and the controller reported in the last check.
+func (c *KafkaChecker) Details() []slog.Attr {
+	if c.lastMetadata == nil {
+		return nil
+	}
+	return []slog.Attr{
+		slog.Int("brokers", c.lastMetadata.brokers),
+		slog.Int("controller_id", int(c.lastMetadata.controllerID)),
+	}
+}
+
+func (c *KafkaChecker) Check(ctx context.Context) error {
+	c.lastMetadata = nil
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := dialTCP(ctx, c.address, c.tlsConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("failed to set deadline: %w", err)
+	}
+
+	versions, err := c.apiVersions(conn)
+	if err != nil {
+		return fmt.Errorf("failed to get API versions: %w", err)
+	}
+
+	if c.username != "" {
+		if err := c.authenticate(conn); err != nil {
+			return fmt.Errorf("SASL authentication failed: %w", err)
+		}
+	}
+
+	version, err := metadataVersion(versions)
+	if err != nil {
+		return err
+	}
+
+	metadata, err := c.metadata(conn, version)
+	if err != nil {
+		return fmt.Errorf("failed to get metadata: %w", err)
+	}
+	c.lastMetadata = metadata
+
+	if metadata.controllerID < 0 {
+		return errors.New("cluster has no active controller")
+	}
+
+	if c.topic == "" {
+		return nil
+	}
+
+	topic, ok := metadata.topics[c.topic]
+	if !ok {
+		return fmt.Errorf("topic %q not included in metadata", c.topic)
+	}
+	if topic.errorCode != 0 {
+		return fmt.Errorf("topic %q is not available: %s", c.topic, kafkaError(topic.errorCode))
+	}
+	if len(topic.partitions) == 0 {
+		return fmt.Errorf("topic %q has no partitions", c.topic)
+	}
+	for _, partition := range topic.partitions {
+		if partition.errorCode != 0 {
+			return fmt.Errorf("partition %d of topic %q is not available: %s", partition.index, c.topic, kafkaError(partition.errorCode))
+		}
+		if partition.leaderID < 0 {
+			return fmt.Errorf("partition %d of topic %q has no leader", partition.index, c.topic)
+		}
+	}
+
+	return nil
+}
+
+// kafkaVersionRange is the range of versions a broker supports for an API key.
+type kafkaVersionRange struct {
+	min, max int16
+}
+
+// apiVersions returns the version ranges supported by the broker.
+func (c *KafkaChecker) apiVersions(conn net.Conn) (map[int16]kafkaVersionRange, error) {
+	d, err := c.roundTrip(conn, kafkaAPIVersions, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	errorCode := d.int16()
+	versions := make(map[int16]kafkaVersionRange)
+	for range d.arrayLen() {
+		key := d.int16()
+		versions[key] = kafkaVersionRange{min: d.int16(), max: d.int16()}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	if errorCode != 0 {
+		return nil, errors.New(kafkaError(errorCode))
+	}
+
+	return versions, nil
+}
+
+// metadataVersion returns the highest Metadata version supported by both the broker and the checker.
+func metadataVersion(versions map[int16]kafkaVersionRange) (int16, error) {
+	supported, ok := versions[kafkaMetadata]
+	if !ok {
+		return 0, errors.New("broker does not support Metadata requests")
+	}
+
+	version := min(supported.max, maxKafkaMetadataVersion)
+	if version < minKafkaMetadataVersion || version < supported.min {
+		return 0, fmt.Errorf("broker supports Metadata versions %d to %d, expected a version between %d and %d",
+			supported.min, supported.max, minKafkaMetadataVersion, maxKafkaMetadataVersion)
+	}
+	return version, nil
+}
+
+// authenticate performs the SASL/PLAIN handshake.
+func (c *KafkaChecker) authenticate(conn net.Conn) error {
+	var e kafkaEncoder
+	e.string("PLAIN")
+	d, err := c.roundTrip(conn, kafkaSaslHandshake, 1, e.buf)
+	if err != nil {
+		return err
+	}
+	errorCode := d.int16()
+	if d.err != nil {
+		return d.err
+	}
+	if errorCode != 0 {
+		return fmt.Errorf("handshake rejected: %s", kafkaError(errorCode))
+	}
+
+	e = kafkaEncoder{}
+	e.bytes([]byte("\x00" + c.username + "\x00" + c.password))
+	d, err = c.roundTrip(conn, kafkaSaslAuthenticate, 0, e.buf)
+	if err != nil {
+		return err
+	}
+	errorCode = d.int16()
+	message := d.nullableString()
+	if d.err != nil {
+		return d.err
+	}
+	if errorCode != 0 {
+		if message != "" {
+			return fmt.Errorf("%s: %s", kafkaError(errorCode), message)
+		}
+		return errors.New(kafkaError(errorCode))
+	}
+
+	return nil
+}
+
+// kafkaMetadataResponse holds the fields of a Metadata response used by the checker.
+type kafkaMetadataResponse struct {
+	brokers      int
+	controllerID int32
+	topics       map[string]kafkaTopicMetadata
+}
+
+// kafkaTopicMetadata holds the state of a topic and its partitions.
+type kafkaTopicMetadata struct {
+	errorCode  int16
+	partitions []kafkaPartitionMetadata
+}
+
+// kafkaPartitionMetadata holds the state of a partition.
+type kafkaPartitionMetadata struct {
+	errorCode int16
+	index     int32
+	leaderID  int32
+}
+
+// metadata requests the metadata of the configured topic, or of no topic at all.
+func (c *KafkaChecker) metadata(conn net.Conn, version int16) (*kafkaMetadataResponse, error) {
+	var e kafkaEncoder
+	if c.topic == "" {
+		e.int32(0) // An empty array requests no topics, null would request all
+	} else {
+		e.int32(1)
+		e.string(c.topic)
+	}
+	e.bool(false) // allow_auto_topic_creation
+	if version >= 8 {
+		e.bool(false) // include_cluster_authorized_operations
+		e.bool(false) // include_topic_authorized_operations
+	}
+
+	d, err := c.roundTrip(conn, kafkaMetadata, version, e.buf)
+	if err != nil {
+		return nil, err
+	}
+	return decodeKafkaMetadata(d, version)
+}
+
+// decodeKafkaMetadata decodes a Metadata response of the given version (4 to 8).
+func decodeKafkaMetadata(d *kafkaDecoder, version int16) (*kafkaMetadataResponse, error) {
+	resp := &kafkaMetadataResponse{topics: make(map[string]kafkaTopicMetadata)}
+
+	d.int32() // throttle_time_ms
+	for range d.arrayLen() {
+		d.int32()          // node_id
+		d.string()         // host
+		d.int32()          // port
+		d.nullableString() // rack
+		resp.brokers++
+	}
+	d.nullableString() // cluster_id
+	resp.controllerID = d.int32()
+
+	for range d.arrayLen() {
+		topic := kafkaTopicMetadata{errorCode: d.int16()}
+		name := d.string()
+		d.bool() // is_internal
+
+		for range d.arrayLen() {
+			partition := kafkaPartitionMetadata{errorCode: d.int16(), index: d.int32(), leaderID: d.int32()}
+			if version >= 7 {
+				d.int32() // leader_epoch
+			}
+			d.int32Array() // replica_nodes
+			d.int32Array() // isr_nodes
+			if version >= 5 {
+				d.int32Array() // offline_replicas
+			}
+			topic.partitions = append(topic.partitions, partition)
+		}
+		if version >= 8 {
+			d.int32() // topic_authorized_operations
+		}
+		resp.topics[name] = topic
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+	return resp, nil
+}
+
+// roundTrip sends a request with a version 1 request header and returns a decoder for the response body.
+func (c *KafkaChecker) roundTrip(conn net.Conn, apiKey, version int16, body []byte) (*kafkaDecoder, error) {
+	c.correlationID++
+
+	var e kafkaEncoder
+	e.int32(0) // Size, set below
+	e.int16(apiKey)
+	e.int16(version)
+	e.int32(c.correlationID)
+	e.string(defaultKafkaClientID)
+	e.buf = append(e.buf, body...)
+	binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+
+	if _, err := conn.Write(e.buf); err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	correlationID, resp, err := readKafkaMessage(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if correlationID != c.correlationID {
+		return nil, fmt.Errorf("unexpected correlation ID %d, expected %d", correlationID, c.correlationID)
+	}
+
+	return &kafkaDecoder{data: resp}, nil
+}
+
+// readKafkaMessage reads a size prefixed message and returns its first int32, which is the correlation ID
+// of a response, and the remaining bytes.
+func readKafkaMessage(r io.Reader) (int32, []byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	size := int(int32(binary.BigEndian.Uint32(header)))
+	if size < 4 || size > maxKafkaResponseSize {
+		return 0, nil, fmt.Errorf("invalid message size %d", size)
+	}
+
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return 0, nil, err
+	}
+
+	return int32(binary.BigEndian.Uint32(msg)), msg[4:], nil
+}
+
+// kafkaEncoder encodes the primitive types of the Kafka protocol.
+type kafkaEncoder struct {
+	buf []byte
+}
+
+func (e *kafkaEncoder) bool(v bool) {
+	if v {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+func (e *kafkaEncoder) int16(v int16) { e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(v)) }
+func (e *kafkaEncoder) int32(v int32) { e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v)) }
+
+func (e *kafkaEncoder) string(v string) {
+	e.int16(int16(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *kafkaEncoder) bytes(v []byte) {
+	e.int32(int32(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+// errKafkaTruncated is returned for responses shorter than their fields.
+var errKafkaTruncated = errors.New("invalid response: truncated")
+
+// kafkaDecoder decodes the primitive types of the Kafka protocol. The first error is kept and
+// all following reads return zero values.
+type kafkaDecoder struct {
+	data []byte
+	err  error
+}
+
+// next returns the next n bytes, or nil if the data is too short.
+func (d *kafkaDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.data) {
+		d.err = errKafkaTruncated
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *kafkaDecoder) bool() bool {
+	if b := d.next(1); b != nil {
+		return b[0] != 0
+	}
+	return false
+}
+
+func (d *kafkaDecoder) int16() int16 {
+	if b := d.next(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int32() int32 {
+	if b := d.next(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) string() string {
+	return string(d.next(int(d.int16())))
+}
+
+// nullableString returns an empty string for null.
+func (d *kafkaDecoder) nullableString() string {
+	size := d.int16()
+	if size < 0 {
+		return ""
+	}
+	return string(d.next(int(size)))
+}
+
+// arrayLen returns the number of elements of an array, or 0 for null.
+func (d *kafkaDecoder) arrayLen() int {
+	size := int(d.int32())
+	if size < 0 || d.err != nil {
+		return 0
+	}
+	if size > len(d.data) { // Every element takes at least one byte
+		d.err = errKafkaTruncated
+		return 0
+	}
+	return size
+}
+
+// int32Array skips an array of int32.
+func (d *kafkaDecoder) int32Array() {
+	d.next(4 * d.arrayLen())
+}
+
+// newKafkaChecker creates a new KafkaChecker with functional options.
+func newKafkaChecker(name, address string, opts ...Option) (*KafkaChecker, error) {
+	checker := &KafkaChecker{
+		name:    name,
+		address: address,
+		timeout: defaultKafkaTimeout,
+	}
+
+	for _, opt := range opts {
+		opt.apply(checker)
+	}
+
+	return checker, nil
+}
+
+// WithKafkaTimeout sets the timeout for connecting and all requests.
+func WithKafkaTimeout(timeout time.Duration) Option {
+	return OptionFunc(func(c Checker) {
+		if kafkaChecker, ok := c.(*KafkaChecker); ok {
+			kafkaChecker.timeout = timeout
+		}
+	})
+}
+
+// WithKafkaTopic requires the topic to exist with a leader for every partition.
+func WithKafkaTopic(topic string) Option {
+	return OptionFunc(func(c Checker) {
+		if kafkaChecker, ok := c.(*KafkaChecker); ok {
+			kafkaChecker.topic = topic
+		}
+	})
+}
+
+// WithKafkaSASLPlain authenticates with SASL/PLAIN.
+func WithKafkaSASLPlain(username, password string) Option {
+	return OptionFunc(func(c Checker) {
+		if kafkaChecker, ok := c.(*KafkaChecker); ok {
+			kafkaChecker.username = username
+			kafkaChecker.password = password
+		}
+	})
+}
+
+// WithKafkaTLS connects with TLS using config.
+func WithKafkaTLS(config *tls.Config) Option {
+	return OptionFunc(func(c Checker) {
+		if kafkaChecker, ok := c.(*KafkaChecker); ok {
+			kafkaChecker.tlsConfig = config
+		}
+	})
+}
@@ -0,0 +1,366 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeKafkaBroker answers ApiVersions, SASL/PLAIN and Metadata requests.
+type fakeKafkaBroker struct {
+	metadataVersions kafkaVersionRange
+	controllerID     int32
+	topics           map[string][]int32 // Partition leaders by topic, a nil slice reports the topic as LEADER_NOT_AVAILABLE
+	username         string             // Requires SASL/PLAIN authentication if set
+	password         string
+}
+
+// startFakeKafkaBroker serves broker on a local listener and returns its address.
+func startFakeKafkaBroker(t *testing.T, broker fakeKafkaBroker, tlsConfig *tls.Config) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	if tlsConfig != nil {
+		ln = tls.NewListener(ln, tlsConfig)
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go broker.serve(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func (b fakeKafkaBroker) serve(conn net.Conn) {
+	defer conn.Close()
+
+	authenticated := b.username == ""
+	for {
+		apiKeyAndVersion, msg, err := readKafkaMessage(conn)
+		if err != nil {
+			return
+		}
+		apiKey, version := int16(apiKeyAndVersion>>16), int16(apiKeyAndVersion)
+
+		req := &kafkaDecoder{data: msg}
+		correlationID := req.int32()
+		req.string() // client_id
+
+		var resp kafkaEncoder
+		resp.int32(0) // Size, set below
+		resp.int32(correlationID)
+
+		switch apiKey {
+		case kafkaAPIVersions:
+			resp.int16(0)
+			resp.int32(4)
+			for _, key := range []int16{kafkaMetadata, kafkaSaslHandshake, kafkaAPIVersions, kafkaSaslAuthenticate} {
+				resp.int16(key)
+				if key == kafkaMetadata {
+					resp.int16(b.metadataVersions.min)
+					resp.int16(b.metadataVersions.max)
+				} else {
+					resp.int16(0)
+					resp.int16(1)
+				}
+			}
+		case kafkaSaslHandshake:
+			resp.int16(0)
+			resp.int32(1)
+			resp.string("PLAIN")
+		case kafkaSaslAuthenticate:
+			if string(req.next(int(req.int32()))) == "\x00"+b.username+"\x00"+b.password {
+				authenticated = true
+				resp.int16(0)
+				resp.int16(-1)
+			} else {
+				resp.int16(58)
+				resp.string("Authentication failed: Invalid username or password")
+			}
+			resp.bytes(nil)
+		case kafkaMetadata:
+			if !authenticated {
+				return // Brokers close unauthenticated connections
+			}
+			b.writeMetadata(&resp, req, version)
+		default:
+			return
+		}
+
+		binary.BigEndian.PutUint32(resp.buf, uint32(len(resp.buf)-4))
+		if _, err := conn.Write(resp.buf); err != nil {
+			return
+		}
+	}
+}
+
+// writeMetadata encodes the Metadata response for the topics in the request.
+func (b fakeKafkaBroker) writeMetadata(resp *kafkaEncoder, req *kafkaDecoder, version int16) {
+	var names []string
+	for range req.arrayLen() {
+		names = append(names, req.string())
+	}
+
+	resp.int32(0) // throttle_time_ms
+	resp.int32(1)
+	resp.int32(1)
+	resp.string("localhost")
+	resp.int32(9092)
+	resp.int16(-1) // rack
+	resp.string("cluster")
+	resp.int32(b.controllerID)
+
+	resp.int32(int32(len(names)))
+	for _, name := range names {
+		leaders, ok := b.topics[name]
+		switch {
+		case !ok:
+			resp.int16(3)
+		case leaders == nil:
+			resp.int16(5)
+		default:
+			resp.int16(0)
+		}
+		resp.string(name)
+		resp.bool(false)
+
+		resp.int32(int32(len(leaders)))
+		for i, leader := range leaders {
+			if leader < 0 {
+				resp.int16(5)
+			} else {
+				resp.int16(0)
+			}
+			resp.int32(int32(i))
+			resp.int32(leader)
+			if version >= 7 {
+				resp.int32(0) // leader_epoch
+			}
+			resp.int32(1) // replica_nodes
+			resp.int32(1)
+			resp.int32(1) // isr_nodes
+			resp.int32(1)
+			if version >= 5 {
+				resp.int32(0) // offline_replicas
+			}
+		}
+		if version >= 8 {
+			resp.int32(0) // topic_authorized_operations
+		}
+	}
+	if version >= 8 {
+		resp.int32(0) // cluster_authorized_operations
+	}
+}
+
+func TestNewKafkaChecker(t *testing.T) {
+	t.Parallel()
+
+	checker, err := newKafkaChecker("example", "localhost:9092",
+		WithKafkaTimeout(3*time.Second),
+		WithKafkaTopic("orders"),
+		WithKafkaSASLPlain("app", "secret"),
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "example", checker.Name())
+	assert.Equal(t, "localhost:9092", checker.Address())
+	assert.Equal(t, Kafka.String(), checker.Type())
+	assert.Equal(t, 3*time.Second, checker.timeout)
+	assert.Equal(t, "orders", checker.topic)
+	assert.Equal(t, "app", checker.username)
+	assert.Equal(t, "secret", checker.password)
+}
+
+func TestKafkaChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	allVersions := kafkaVersionRange{min: 0, max: 12}
+	topics := map[string][]int32{"orders": {1, 2, 3}, "payments": {1, -1}, "creating": nil}
+
+	t.Run("Cluster Ready", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeKafkaBroker(t, fakeKafkaBroker{metadataVersions: allVersions, controllerID: 1}, nil)
+		checker, err := newKafkaChecker("kafka", address)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+		assert.Equal(t, int64(1), checker.Details()[0].Value.Int64())
+		assert.Equal(t, int64(1), checker.Details()[1].Value.Int64())
+	})
+
+	t.Run("No Controller", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeKafkaBroker(t, fakeKafkaBroker{metadataVersions: allVersions, controllerID: -1}, nil)
+		checker, err := newKafkaChecker("kafka", address)
+		assert.NoError(t, err)
+
+		assert.EqualError(t, checker.Check(context.Background()), "cluster has no active controller")
+	})
+
+	for _, versions := range []kafkaVersionRange{{0, 4}, {0, 6}, {1, 7}, {0, 12}} {
+		t.Run(fmt.Sprintf("Topic With Metadata Versions %d To %d", versions.min, versions.max), func(t *testing.T) {
+			t.Parallel()
+
+			address := startFakeKafkaBroker(t, fakeKafkaBroker{metadataVersions: versions, controllerID: 1, topics: topics}, nil)
+			checker, err := newKafkaChecker("kafka", address, WithKafkaTopic("orders"))
+			assert.NoError(t, err)
+
+			assert.NoError(t, checker.Check(context.Background()))
+		})
+	}
+
+	t.Run("Unsupported Metadata Versions", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeKafkaBroker(t, fakeKafkaBroker{metadataVersions: kafkaVersionRange{0, 3}, controllerID: 1}, nil)
+		checker, err := newKafkaChecker("kafka", address)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "broker supports Metadata versions 0 to 3, expected a version between 4 and 8")
+	})
+
+	t.Run("Unknown Topic", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeKafkaBroker(t, fakeKafkaBroker{metadataVersions: allVersions, controllerID: 1, topics: topics}, nil)
+		checker, err := newKafkaChecker("kafka", address, WithKafkaTopic("missing"))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, `topic "missing" is not available: UNKNOWN_TOPIC_OR_PARTITION (3)`)
+	})
+
+	t.Run("Topic Leader Not Available", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeKafkaBroker(t, fakeKafkaBroker{metadataVersions: allVersions, controllerID: 1, topics: topics}, nil)
+		checker, err := newKafkaChecker("kafka", address, WithKafkaTopic("creating"))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, `topic "creating" is not available: LEADER_NOT_AVAILABLE (5)`)
+	})
+
+	t.Run("Partition Without Leader", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeKafkaBroker(t, fakeKafkaBroker{metadataVersions: allVersions, controllerID: 1, topics: topics}, nil)
+		checker, err := newKafkaChecker("kafka", address, WithKafkaTopic("payments"))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, `partition 1 of topic "payments" is not available: LEADER_NOT_AVAILABLE (5)`)
+	})
+
+	t.Run("SASL PLAIN", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeKafkaBroker(t, fakeKafkaBroker{
+			metadataVersions: allVersions,
+			controllerID:     1,
+			topics:           topics,
+			username:         "app",
+			password:         "secret",
+		}, nil)
+
+		checker, err := newKafkaChecker("kafka", address, WithKafkaTopic("orders"), WithKafkaSASLPlain("app", "secret"))
+		assert.NoError(t, err)
+		assert.NoError(t, checker.Check(context.Background()))
+
+		checker, err = newKafkaChecker("kafka", address, WithKafkaSASLPlain("app", "wrong"))
+		assert.NoError(t, err)
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "SASL authentication failed: SASL_AUTHENTICATION_FAILED (58): Authentication failed: Invalid username or password")
+	})
+
+	t.Run("TLS", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewUnstartedServer(nil)
+		server.StartTLS()
+		pool := x509.NewCertPool()
+		pool.AddCert(server.Certificate())
+		tlsConfig := &tls.Config{Certificates: server.TLS.Certificates}
+		server.Close()
+
+		address := startFakeKafkaBroker(t, fakeKafkaBroker{metadataVersions: allVersions, controllerID: 1}, tlsConfig)
+		checker, err := newKafkaChecker("kafka", address, WithKafkaTLS(&tls.Config{RootCAs: pool}))
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Connection Closed", func(t *testing.T) {
+		t.Parallel()
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		t.Cleanup(func() { _ = ln.Close() })
+		go func() {
+			conn, err := ln.Accept()
+			if err == nil {
+				_ = conn.Close()
+			}
+		}()
+
+		checker, err := newKafkaChecker("kafka", ln.Addr().String())
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get API versions: ")
+		assert.Nil(t, checker.Details())
+	})
+}
+
+func TestKafkaDecoder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Truncated", func(t *testing.T) {
+		t.Parallel()
+
+		d := &kafkaDecoder{data: []byte{0, 5, 'a', 'b'}}
+		assert.Equal(t, "", d.string())
+		assert.Equal(t, int32(0), d.int32())
+		assert.ErrorIs(t, d.err, errKafkaTruncated)
+	})
+
+	t.Run("Array Longer Than Data", func(t *testing.T) {
+		t.Parallel()
+
+		d := &kafkaDecoder{data: []byte{0x7f, 0xff, 0xff, 0xff}}
+		assert.Equal(t, 0, d.arrayLen())
+		assert.ErrorIs(t, d.err, errKafkaTruncated)
+	})
+
+	t.Run("Null Values", func(t *testing.T) {
+		t.Parallel()
+
+		d := &kafkaDecoder{data: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}}
+		assert.Equal(t, "", d.nullableString())
+		assert.Equal(t, 0, d.arrayLen())
+		assert.NoError(t, d.err)
+	})
+}
@@ -0,0 +1,440 @@
+package checker
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultMongoDBTimeout    time.Duration = 2 * time.Second
+	defaultMongoDBAuthSource string        = "admin"
+	maxMongoDBMessageSize    int           = 16 * 1024 * 1024 // Largest reply accepted, matching the maximum BSON document size
+	mongoDBMaxSASLExchanges  int           = 5                // Limits the saslContinue round trips after the proof was verified
+	mongoDBSCRAMNonceLength  int           = 24
+
+	mongoDBOpMsg           int32  = 2013 // OP_MSG, supported since MongoDB 3.6
+	mongoDBChecksumPresent uint32 = 1    // OP_MSG flag bit of a trailing CRC-32C checksum
+	mongoDBCommandNotFound int    = 59   // Error code returned by servers not supporting hello
+)
+
+// SCRAM mechanisms supported by the MongoDBChecker.
+const (
+	MongoDBAuthSCRAMSHA1   string = "SCRAM-SHA-1"
+	MongoDBAuthSCRAMSHA256 string = "SCRAM-SHA-256"
+)
+
+// MongoDBRole is the replica set member state required by a MongoDBChecker.
+type MongoDBRole string
+
+const (
+	MongoDBAnyRole   MongoDBRole = ""          // Any node answering hello is ready
+	MongoDBPrimary   MongoDBRole = "primary"   // The node must accept writes
+	MongoDBSecondary MongoDBRole = "secondary" // The node must be a replicating secondary
+)
+
+// mongoDBRequestID numbers the requests sent by all MongoDB checkers.
+var mongoDBRequestID atomic.Int32
+
+// MongoDBChecker implements the Checker interface by running the hello command over the OP_MSG wire protocol.
+type MongoDBChecker struct {
+	name          string
+	address       string
+	timeout       time.Duration // Timeout for connecting and running all commands
+	role          MongoDBRole   // Required member state
+	replicaSet    string        // Required replica set name, not checked if empty
+	username      string        // Authenticates with SCRAM if set
+	password      string
+	authSource    string // Database holding the user
+	authMechanism string // SCRAM-SHA-256 or SCRAM-SHA-1
+	tlsConfig     *tls.Config
+	lastHello     *mongoDBHello
+}
+
+// mongoDBHello holds the fields of a hello reply used by the checker.
+type mongoDBHello struct {
+	writablePrimary bool
+	secondary       bool
+	arbiter         bool
+	setName         string
+}
+
+// role returns the member state reported in the hello reply.
+func (h *mongoDBHello) role() string {
+	switch {
+	case h.writablePrimary:
+		return "primary"
+	case h.secondary:
+		return "secondary"
+	case h.arbiter:
+		return "arbiter"
+	default:
+		return "other"
+	}
+}
+
+// mongoDBCommandError is a command reply with "ok" not set to 1.
+type mongoDBCommandError struct {
+	Code     int
+	CodeName string
+	Message  string
+}
+
+func (e *mongoDBCommandError) Error() string {
+	if e.CodeName != "" {
+		return fmt.Sprintf("%s (%s)", e.Message, e.CodeName)
+	}
+	return e.Message
+}
+
+func (c *MongoDBChecker) Address() string { return c.address }
+func (c *MongoDBChecker) Name() string    { return c.name }
+func (c *MongoDBChecker) Type() string    { return MongoDB.String() }
+
+// Details returns the member state and replica set reported by the node in the last check.
+func (c *MongoDBChecker) Details() []slog.Attr {
+	if c.lastHello == nil {
+		return nil
+	}
+
+	attrs := []slog.Attr{slog.String("role", c.lastHello.role())}
+	if c.lastHello.setName != "" {
+		attrs = append(attrs, slog.String("replica_set", c.lastHello.setName))
+	}
+	return attrs
+}
+
+func (c *MongoDBChecker) Check(ctx context.Context) error {
+	c.lastHello = nil
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := dialTCP(ctx, strings.TrimPrefix(c.address, "mongodb://"), c.tlsConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("failed to set deadline: %w", err)
+	}
+
+	hello, err := runHello(conn)
+	if err != nil {
+		return err
+	}
+	c.lastHello = hello
+
+	if c.username != "" {
+		if err := c.authenticate(conn); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	switch c.role {
+	case MongoDBPrimary:
+		if !hello.writablePrimary {
+			return fmt.Errorf("node is not a writable primary: role is %s", hello.role())
+		}
+	case MongoDBSecondary:
+		if !hello.secondary {
+			return fmt.Errorf("node is not a secondary: role is %s", hello.role())
+		}
+	}
+
+	if c.replicaSet != "" {
+		if hello.setName == "" {
+			return fmt.Errorf("node is not a member of replica set %q", c.replicaSet)
+		}
+		if hello.setName != c.replicaSet {
+			return fmt.Errorf("unexpected replica set: got %q, expected %q", hello.setName, c.replicaSet)
+		}
+	}
+
+	return nil
+}
+
+// runHello runs hello, falling back to the legacy isMaster command on servers older than MongoDB 4.4.2.
+func runHello(conn net.Conn) (*mongoDBHello, error) {
+	reply, err := runMongoDBCommand(conn, bsonDoc{{"hello", int32(1)}, {"$db", "admin"}})
+
+	var cmdErr *mongoDBCommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == mongoDBCommandNotFound {
+		reply, err = runMongoDBCommand(conn, bsonDoc{{"isMaster", int32(1)}, {"$db", "admin"}})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("hello failed: %w", err)
+	}
+
+	hello := &mongoDBHello{}
+	hello.writablePrimary, _ = reply["isWritablePrimary"].(bool)
+	if legacy, ok := reply["ismaster"].(bool); ok {
+		hello.writablePrimary = hello.writablePrimary || legacy
+	}
+	hello.secondary, _ = reply["secondary"].(bool)
+	hello.arbiter, _ = reply["arbiterOnly"].(bool)
+	hello.setName, _ = reply["setName"].(string)
+
+	return hello, nil
+}
+
+// authenticate runs the SCRAM conversation with saslStart and saslContinue.
+func (c *MongoDBChecker) authenticate(conn net.Conn) error {
+	var h func() hash.Hash
+	password := c.password
+
+	switch c.authMechanism {
+	case MongoDBAuthSCRAMSHA256:
+		h = sha256.New
+	case MongoDBAuthSCRAMSHA1:
+		// SCRAM-SHA-1 uses the legacy MONGODB-CR password digest as password
+		digest := md5.Sum([]byte(c.username + ":mongo:" + c.password))
+		password = hex.EncodeToString(digest[:])
+		h = sha1.New
+	default:
+		return fmt.Errorf("unsupported mechanism %q", c.authMechanism)
+	}
+
+	nonce := make([]byte, mongoDBSCRAMNonceLength)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	client := newSCRAMClient(h, c.username, password, base64.StdEncoding.EncodeToString(nonce))
+
+	reply, err := runMongoDBCommand(conn, bsonDoc{
+		{"saslStart", int32(1)},
+		{"mechanism", c.authMechanism},
+		{"payload", []byte(client.clientFirst())},
+		{"autoAuthorize", int32(1)},
+		{"options", bsonDoc{{"skipEmptyExchange", true}}},
+		{"$db", c.authSource},
+	})
+	if err != nil {
+		return err
+	}
+
+	serverFirst, _ := reply["payload"].([]byte)
+	clientFinal, err := client.clientFinal(string(serverFirst))
+	if err != nil {
+		return err
+	}
+
+	reply, err = runMongoDBCommand(conn, bsonDoc{
+		{"saslContinue", int32(1)},
+		{"conversationId", reply["conversationId"]},
+		{"payload", []byte(clientFinal)},
+		{"$db", c.authSource},
+	})
+	if err != nil {
+		return err
+	}
+
+	serverFinal, _ := reply["payload"].([]byte)
+	if err := client.verifyServerFinal(string(serverFinal)); err != nil {
+		return err
+	}
+
+	// Servers not supporting skipEmptyExchange expect an empty message to complete the conversation
+	for range mongoDBMaxSASLExchanges {
+		if done, _ := reply["done"].(bool); done {
+			return nil
+		}
+		reply, err = runMongoDBCommand(conn, bsonDoc{
+			{"saslContinue", int32(1)},
+			{"conversationId", reply["conversationId"]},
+			{"payload", []byte{}},
+			{"$db", c.authSource},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return errors.New("conversation did not complete")
+}
+
+// runMongoDBCommand sends a command and returns the reply, or a *mongoDBCommandError if the command failed.
+func runMongoDBCommand(conn net.Conn, cmd bsonDoc) (map[string]any, error) {
+	requestID := mongoDBRequestID.Add(1)
+	if err := writeMongoDBMessage(conn, requestID, 0, cmd); err != nil {
+		return nil, fmt.Errorf("failed to send command: %w", err)
+	}
+
+	_, responseTo, reply, err := readMongoDBMessage(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reply: %w", err)
+	}
+	if responseTo != requestID {
+		return nil, fmt.Errorf("unexpected reply to request %d, expected %d", responseTo, requestID)
+	}
+
+	if ok, _ := bsonNumber(reply["ok"]); ok != 1 {
+		code, _ := bsonNumber(reply["code"])
+		codeName, _ := reply["codeName"].(string)
+		message, _ := reply["errmsg"].(string)
+		return nil, &mongoDBCommandError{Code: int(code), CodeName: codeName, Message: message}
+	}
+
+	return reply, nil
+}
+
+// writeMongoDBMessage writes doc as the body section of an OP_MSG.
+func writeMongoDBMessage(w io.Writer, requestID, responseTo int32, doc bsonDoc) error {
+	body, err := marshalBSON(doc)
+	if err != nil {
+		return err
+	}
+
+	msg := make([]byte, 16, 16+4+1+len(body))
+	binary.LittleEndian.PutUint32(msg[4:], uint32(requestID))
+	binary.LittleEndian.PutUint32(msg[8:], uint32(responseTo))
+	binary.LittleEndian.PutUint32(msg[12:], uint32(mongoDBOpMsg))
+	msg = binary.LittleEndian.AppendUint32(msg, 0) // Flag bits
+	msg = append(msg, 0)                           // Section kind 0: body
+	msg = append(msg, body...)
+	binary.LittleEndian.PutUint32(msg, uint32(len(msg)))
+
+	_, err = w.Write(msg)
+	return err
+}
+
+// readMongoDBMessage reads an OP_MSG and returns its header IDs and body section.
+func readMongoDBMessage(r io.Reader) (requestID, responseTo int32, doc map[string]any, err error) {
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, 0, nil, err
+	}
+
+	length := int(int32(binary.LittleEndian.Uint32(header)))
+	requestID = int32(binary.LittleEndian.Uint32(header[4:]))
+	responseTo = int32(binary.LittleEndian.Uint32(header[8:]))
+	if opCode := int32(binary.LittleEndian.Uint32(header[12:])); opCode != mongoDBOpMsg {
+		return 0, 0, nil, fmt.Errorf("unexpected opcode %d", opCode)
+	}
+	if length < 16+4+1+5 || length > maxMongoDBMessageSize {
+		return 0, 0, nil, fmt.Errorf("invalid message length %d", length)
+	}
+
+	msg := make([]byte, length-16)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return 0, 0, nil, err
+	}
+
+	flags := binary.LittleEndian.Uint32(msg)
+	sections := msg[4:]
+	if flags&mongoDBChecksumPresent != 0 {
+		sections = sections[:len(sections)-4]
+	}
+
+	for len(sections) > 0 {
+		kind := sections[0]
+		sections = sections[1:]
+
+		switch kind {
+		case 0:
+			var n int
+			if doc, n, err = decodeBSONDocument(sections); err != nil {
+				return 0, 0, nil, err
+			}
+			sections = sections[n:]
+		case 1: // Document sequence, not used in replies to the commands sent
+			if len(sections) < 4 {
+				return 0, 0, nil, errBSONTruncated
+			}
+			size := int(binary.LittleEndian.Uint32(sections))
+			if size < 4 || size > len(sections) {
+				return 0, 0, nil, errBSONTruncated
+			}
+			sections = sections[size:]
+		default:
+			return 0, 0, nil, fmt.Errorf("unsupported section kind %d", kind)
+		}
+	}
+
+	if doc == nil {
+		return 0, 0, nil, errors.New("message has no body section")
+	}
+	return requestID, responseTo, doc, nil
+}
+
+// newMongoDBChecker creates a new MongoDBChecker with functional options.
+func newMongoDBChecker(name, address string, opts ...Option) (*MongoDBChecker, error) {
+	checker := &MongoDBChecker{
+		name:          name,
+		address:       address,
+		timeout:       defaultMongoDBTimeout,
+		authSource:    defaultMongoDBAuthSource,
+		authMechanism: MongoDBAuthSCRAMSHA256,
+	}
+
+	for _, opt := range opts {
+		opt.apply(checker)
+	}
+
+	return checker, nil
+}
+
+// WithMongoDBTimeout sets the timeout for connecting and running all commands.
+func WithMongoDBTimeout(timeout time.Duration) Option {
+	return OptionFunc(func(c Checker) {
+		if mongoChecker, ok := c.(*MongoDBChecker); ok {
+			mongoChecker.timeout = timeout
+		}
+	})
+}
+
+// WithMongoDBRole requires the node to be in the given member state.
+func WithMongoDBRole(role MongoDBRole) Option {
+	return OptionFunc(func(c Checker) {
+		if mongoChecker, ok := c.(*MongoDBChecker); ok {
+			mongoChecker.role = role
+		}
+	})
+}
+
+// WithMongoDBReplicaSet requires the node to be a member of the named replica set.
+func WithMongoDBReplicaSet(name string) Option {
+	return OptionFunc(func(c Checker) {
+		if mongoChecker, ok := c.(*MongoDBChecker); ok {
+			mongoChecker.replicaSet = name
+		}
+	})
+}
+
+// WithMongoDBAuth authenticates with the given user, stored in authSource, using a SCRAM mechanism.
+func WithMongoDBAuth(username, password, authSource, mechanism string) Option {
+	return OptionFunc(func(c Checker) {
+		if mongoChecker, ok := c.(*MongoDBChecker); ok {
+			mongoChecker.username = username
+			mongoChecker.password = password
+			mongoChecker.authSource = authSource
+			mongoChecker.authMechanism = mechanism
+		}
+	})
+}
+
+// WithMongoDBTLS connects with TLS using config.
+func WithMongoDBTLS(config *tls.Config) Option {
+	return OptionFunc(func(c Checker) {
+		if mongoChecker, ok := c.(*MongoDBChecker); ok {
+			mongoChecker.tlsConfig = config
+		}
+	})
+}
@@ -0,0 +1,275 @@
+package checker
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMongoDB answers hello and a SCRAM-SHA-256 conversation over OP_MSG.
+type fakeMongoDB struct {
+	hello         bsonDoc // Reply to hello
+	legacyOnly    bool    // Reject hello like servers before MongoDB 4.4.2
+	username      string
+	password      string
+	emptyExchange bool // Require an empty saslContinue like servers ignoring skipEmptyExchange
+	silent        bool // Never reply
+}
+
+// startFakeMongoDB serves server on a local listener and returns its address.
+func startFakeMongoDB(t *testing.T, server fakeMongoDB, tlsConfig *tls.Config) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	if tlsConfig != nil {
+		ln = tls.NewListener(ln, tlsConfig)
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func (s fakeMongoDB) serve(conn net.Conn) {
+	defer conn.Close()
+
+	var scram *scramClient
+	var serverFirst string
+
+	for {
+		requestID, _, cmd, err := readMongoDBMessage(conn)
+		if err != nil {
+			return
+		}
+		if s.silent {
+			continue
+		}
+
+		reply := bsonDoc{{"ok", 0.0}, {"errmsg", "no such command"}, {"code", int32(59)}, {"codeName", "CommandNotFound"}}
+		switch {
+		case cmd["hello"] != nil && !s.legacyOnly, cmd["isMaster"] != nil:
+			reply = append(s.hello, bsonElement{"ok", 1.0})
+		case cmd["saslStart"] != nil:
+			payload, _ := cmd["payload"].([]byte)
+			nonce := parseSCRAMAttributes(string(payload))["r"]
+			scram = newSCRAMClient(sha256.New, s.username, s.password, nonce)
+			serverFirst = "r=" + nonce + "server,s=" + base64.StdEncoding.EncodeToString([]byte("salt")) + ",i=4096"
+			reply = bsonDoc{{"conversationId", int32(1)}, {"done", false}, {"payload", []byte(serverFirst)}, {"ok", 1.0}}
+		case cmd["saslContinue"] != nil:
+			payload, _ := cmd["payload"].([]byte)
+			if len(payload) == 0 {
+				reply = bsonDoc{{"conversationId", int32(1)}, {"done", true}, {"payload", []byte{}}, {"ok", 1.0}}
+				break
+			}
+			expected, _ := scram.clientFinal(serverFirst)
+			if string(payload) != expected {
+				reply = bsonDoc{{"ok", 0.0}, {"errmsg", "Authentication failed."}, {"code", int32(18)}, {"codeName", "AuthenticationFailed"}}
+				break
+			}
+			serverFinal := "v=" + base64.StdEncoding.EncodeToString(scram.serverSignature)
+			reply = bsonDoc{{"conversationId", int32(1)}, {"done", !s.emptyExchange}, {"payload", []byte(serverFinal)}, {"ok", 1.0}}
+		}
+
+		if err := writeMongoDBMessage(conn, 0, requestID, reply); err != nil {
+			return
+		}
+	}
+}
+
+func TestNewMongoDBChecker(t *testing.T) {
+	t.Parallel()
+
+	checker, err := newMongoDBChecker("example", "localhost:27017",
+		WithMongoDBTimeout(3*time.Second),
+		WithMongoDBRole(MongoDBPrimary),
+		WithMongoDBReplicaSet("rs0"),
+		WithMongoDBAuth("app", "secret", "app", MongoDBAuthSCRAMSHA1),
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "example", checker.Name())
+	assert.Equal(t, "localhost:27017", checker.Address())
+	assert.Equal(t, MongoDB.String(), checker.Type())
+	assert.Equal(t, 3*time.Second, checker.timeout)
+	assert.Equal(t, MongoDBPrimary, checker.role)
+	assert.Equal(t, "rs0", checker.replicaSet)
+	assert.Equal(t, "app", checker.username)
+	assert.Equal(t, "app", checker.authSource)
+	assert.Equal(t, MongoDBAuthSCRAMSHA1, checker.authMechanism)
+}
+
+func TestMongoDBChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	primary := bsonDoc{{"isWritablePrimary", true}, {"secondary", false}, {"setName", "rs0"}}
+	secondary := bsonDoc{{"isWritablePrimary", false}, {"secondary", true}, {"setName", "rs0"}}
+	electing := bsonDoc{{"isWritablePrimary", false}, {"secondary", false}, {"setName", "rs0"}}
+	standalone := bsonDoc{{"isWritablePrimary", true}}
+
+	t.Run("Standalone", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeMongoDB(t, fakeMongoDB{hello: standalone}, nil)
+		checker, err := newMongoDBChecker("mongo", "mongodb://"+address)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+		assert.Len(t, checker.Details(), 1)
+		assert.Equal(t, "primary", checker.Details()[0].Value.String())
+	})
+
+	t.Run("Writable Primary", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeMongoDB(t, fakeMongoDB{hello: primary}, nil)
+		checker, err := newMongoDBChecker("mongo", address, WithMongoDBRole(MongoDBPrimary), WithMongoDBReplicaSet("rs0"))
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+		assert.Equal(t, "rs0", checker.Details()[1].Value.String())
+	})
+
+	t.Run("Election In Progress", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeMongoDB(t, fakeMongoDB{hello: electing}, nil)
+		checker, err := newMongoDBChecker("mongo", address, WithMongoDBRole(MongoDBPrimary))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "node is not a writable primary: role is other")
+		assert.Equal(t, "other", checker.Details()[0].Value.String())
+	})
+
+	t.Run("Secondary", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeMongoDB(t, fakeMongoDB{hello: secondary}, nil)
+
+		checker, err := newMongoDBChecker("mongo", address, WithMongoDBRole(MongoDBSecondary))
+		assert.NoError(t, err)
+		assert.NoError(t, checker.Check(context.Background()))
+
+		checker, err = newMongoDBChecker("mongo", address, WithMongoDBRole(MongoDBPrimary))
+		assert.NoError(t, err)
+		assert.EqualError(t, checker.Check(context.Background()), "node is not a writable primary: role is secondary")
+	})
+
+	t.Run("Not A Secondary", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeMongoDB(t, fakeMongoDB{hello: primary}, nil)
+		checker, err := newMongoDBChecker("mongo", address, WithMongoDBRole(MongoDBSecondary))
+		assert.NoError(t, err)
+
+		assert.EqualError(t, checker.Check(context.Background()), "node is not a secondary: role is primary")
+	})
+
+	t.Run("Replica Set", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeMongoDB(t, fakeMongoDB{hello: primary}, nil)
+		checker, err := newMongoDBChecker("mongo", address, WithMongoDBReplicaSet("rs1"))
+		assert.NoError(t, err)
+		assert.EqualError(t, checker.Check(context.Background()), `unexpected replica set: got "rs0", expected "rs1"`)
+
+		address = startFakeMongoDB(t, fakeMongoDB{hello: standalone}, nil)
+		checker, err = newMongoDBChecker("mongo", address, WithMongoDBReplicaSet("rs1"))
+		assert.NoError(t, err)
+		assert.EqualError(t, checker.Check(context.Background()), `node is not a member of replica set "rs1"`)
+	})
+
+	t.Run("Legacy isMaster", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeMongoDB(t, fakeMongoDB{hello: bsonDoc{{"ismaster", true}}, legacyOnly: true}, nil)
+		checker, err := newMongoDBChecker("mongo", address, WithMongoDBRole(MongoDBPrimary))
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Authentication", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeMongoDB(t, fakeMongoDB{hello: primary, username: "app", password: "secret"}, nil)
+
+		checker, err := newMongoDBChecker("mongo", address, WithMongoDBAuth("app", "secret", "admin", MongoDBAuthSCRAMSHA256))
+		assert.NoError(t, err)
+		assert.NoError(t, checker.Check(context.Background()))
+
+		checker, err = newMongoDBChecker("mongo", address, WithMongoDBAuth("app", "wrong", "admin", MongoDBAuthSCRAMSHA256))
+		assert.NoError(t, err)
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "authentication failed: Authentication failed. (AuthenticationFailed)")
+	})
+
+	t.Run("Authentication With Empty Exchange", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeMongoDB(t, fakeMongoDB{hello: primary, username: "app", password: "secret", emptyExchange: true}, nil)
+		checker, err := newMongoDBChecker("mongo", address, WithMongoDBAuth("app", "secret", "admin", MongoDBAuthSCRAMSHA256))
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("TLS", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewUnstartedServer(nil)
+		server.StartTLS()
+		certificate := server.Certificate()
+		tlsConfig := &tls.Config{Certificates: server.TLS.Certificates}
+		server.Close()
+
+		address := startFakeMongoDB(t, fakeMongoDB{hello: primary}, tlsConfig)
+
+		pool := x509.NewCertPool()
+		pool.AddCert(certificate)
+		checker, err := newMongoDBChecker("mongo", address, WithMongoDBTLS(&tls.Config{RootCAs: pool}))
+		assert.NoError(t, err)
+		assert.NoError(t, checker.Check(context.Background()))
+
+		checker, err = newMongoDBChecker("mongo", address, WithMongoDBTLS(&tls.Config{}))
+		assert.NoError(t, err)
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "TLS handshake failed: "))
+	})
+
+	t.Run("No Reply", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeMongoDB(t, fakeMongoDB{silent: true}, nil)
+		checker, err := newMongoDBChecker("mongo", address, WithMongoDBTimeout(100*time.Millisecond))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "hello failed: failed to read reply: ")
+		assert.Contains(t, err.Error(), "i/o timeout")
+		assert.Nil(t, checker.Details())
+	})
+}
@@ -0,0 +1,469 @@
+package checker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultMQTTTimeout        time.Duration = 2 * time.Second
+	defaultMQTTClientIDPrefix string        = "portpatrol-"
+	maxMQTTPacketSize         int           = 256 * 1024 // Largest packet read, the body of larger packets is discarded
+)
+
+// errMQTTPacketTooLarge is returned by readMQTTPacket for packets exceeding maxMQTTPacketSize.
+var errMQTTPacketTooLarge = fmt.Errorf("packet exceeds %d bytes", maxMQTTPacketSize)
+
+// MQTT protocol levels sent in CONNECT.
+const (
+	MQTTVersion311 byte = 4
+	MQTTVersion5   byte = 5
+)
+
+// MQTT control packet types, shifted into the upper nibble of the fixed header.
+const (
+	mqttConnect    byte = 0x10
+	mqttConnAck    byte = 0x20
+	mqttPublish    byte = 0x30
+	mqttSubscribe  byte = 0x82 // Flags 0b0010 are required for SUBSCRIBE
+	mqttSubAck     byte = 0x90
+	mqttDisconnect byte = 0xE0
+)
+
+// mqttConnectReturnCodes holds the CONNACK return codes of MQTT 3.1.1.
+var mqttConnectReturnCodes = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+// mqttReasonCodes holds the failure reason codes of MQTT 5 used in CONNACK and SUBACK.
+var mqttReasonCodes = map[byte]string{
+	0x80: "unspecified error",
+	0x81: "malformed packet",
+	0x82: "protocol error",
+	0x83: "implementation specific error",
+	0x84: "unsupported protocol version",
+	0x85: "client identifier not valid",
+	0x86: "bad user name or password",
+	0x87: "not authorized",
+	0x88: "server unavailable",
+	0x89: "server busy",
+	0x8A: "banned",
+	0x8C: "bad authentication method",
+	0x8F: "topic filter invalid",
+	0x90: "topic name invalid",
+	0x97: "quota exceeded",
+	0x9F: "connection rate exceeded",
+}
+
+// MQTTChecker implements the Checker interface by connecting to an MQTT broker.
+type MQTTChecker struct {
+	name      string
+	address   string
+	version   byte          // Protocol level, MQTTVersion311 or MQTTVersion5
+	clientID  string        // Generated for each check if empty
+	username  string        // Sent in CONNECT if set
+	password  string        // Sent in CONNECT if set, MQTT 3.1.1 requires a username
+	tlsConfig *tls.Config   // TLS configuration for mqtts, verifies against the system CAs if nil
+	timeout   time.Duration // Timeout for the whole check
+	topic     string        // Topic for the subscribe/publish round trip, skipped if empty
+}
+
+func (c *MQTTChecker) Address() string { return c.address }
+func (c *MQTTChecker) Name() string    { return c.name }
+func (c *MQTTChecker) Type() string    { return MQTT.String() }
+
+func (c *MQTTChecker) Check(ctx context.Context) error {
+	u, err := url.Parse(c.address)
+	if err != nil {
+		return fmt.Errorf("invalid address: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := c.dial(ctx, u)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("failed to set deadline: %w", err)
+	}
+
+	r := bufio.NewReader(conn)
+	if err := c.connect(conn, r); err != nil {
+		return err
+	}
+
+	if c.topic != "" {
+		if err := c.roundTrip(conn, r); err != nil {
+			return err
+		}
+	}
+
+	if _, err := conn.Write([]byte{mqttDisconnect, 0}); err != nil {
+		return fmt.Errorf("failed to send DISCONNECT: %w", err)
+	}
+
+	return nil
+}
+
+// dial connects to the host of u, using TLS for mqtts.
+func (c *MQTTChecker) dial(ctx context.Context, u *url.URL) (net.Conn, error) {
+	var port string
+	var tlsConfig *tls.Config
+	switch u.Scheme {
+	case "mqtt":
+		port = "1883"
+	case "mqtts":
+		port = "8883"
+		tlsConfig = c.tlsConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q: must be mqtt or mqtts", u.Scheme)
+	}
+	if u.Port() != "" {
+		port = u.Port()
+	}
+
+	return dialTCP(ctx, net.JoinHostPort(u.Hostname(), port), tlsConfig)
+}
+
+// connect sends CONNECT and waits for a successful CONNACK.
+func (c *MQTTChecker) connect(conn net.Conn, r *bufio.Reader) error {
+	clientID := c.clientID
+	if clientID == "" {
+		suffix := make([]byte, 4) // Keeps the identifier within the 23 bytes every 3.1.1 broker must accept
+		if _, err := rand.Read(suffix); err != nil {
+			return fmt.Errorf("failed to generate client ID: %w", err)
+		}
+		clientID = defaultMQTTClientIDPrefix + hex.EncodeToString(suffix)
+	}
+
+	// MQTT 5 allows a password without a user name, e.g. for token based authentication
+	if c.password != "" && c.username == "" && c.version != MQTTVersion5 {
+		return errors.New("a password requires a username with MQTT 3.1.1")
+	}
+
+	flags := byte(0x02) // Clean session
+	if c.username != "" {
+		flags |= 0x80
+	}
+	if c.password != "" {
+		flags |= 0x40
+	}
+
+	body := appendMQTTString(nil, "MQTT")
+	body = append(body, c.version, flags)
+	body = binary.BigEndian.AppendUint16(body, 0) // Keep alive is not needed for a single check
+	if c.version == MQTTVersion5 {
+		body = append(body, 0) // No properties
+	}
+	body = appendMQTTString(body, clientID)
+	if c.username != "" {
+		body = appendMQTTString(body, c.username)
+	}
+	if c.password != "" {
+		body = appendMQTTString(body, c.password)
+	}
+
+	if err := writeMQTTPacket(conn, mqttConnect, body); err != nil {
+		return fmt.Errorf("failed to send CONNECT: %w", err)
+	}
+
+	packetType, resp, err := readMQTTPacket(r)
+	if err != nil {
+		return fmt.Errorf("failed to read CONNACK: %w", err)
+	}
+	if packetType&0xF0 != mqttConnAck || len(resp) < 2 {
+		return fmt.Errorf("unexpected packet type %d, expected CONNACK", packetType>>4)
+	}
+
+	if code := resp[1]; code != 0 {
+		// Brokers without MQTT 5 support answer a 3.1.1 return code, MQTT 5 reason codes start at 0x80
+		if c.version == MQTTVersion5 && code >= 0x80 {
+			return fmt.Errorf("connection refused by broker: %s", mqttReasonCode(code))
+		}
+		if reason, ok := mqttConnectReturnCodes[code]; ok {
+			return fmt.Errorf("connection refused by broker: %s (%d)", reason, code)
+		}
+		return fmt.Errorf("connection refused by broker: return code %d", code)
+	}
+
+	return nil
+}
+
+// roundTrip subscribes to the topic, publishes a unique message to it and waits until the message is received.
+func (c *MQTTChecker) roundTrip(conn net.Conn, r *bufio.Reader) error {
+	const packetID uint16 = 1
+
+	body := binary.BigEndian.AppendUint16(nil, packetID)
+	if c.version == MQTTVersion5 {
+		body = append(body, 0) // No properties
+	}
+	body = appendMQTTString(body, c.topic)
+	body = append(body, 0) // Maximum QoS 0
+	if err := writeMQTTPacket(conn, mqttSubscribe, body); err != nil {
+		return fmt.Errorf("failed to send SUBSCRIBE: %w", err)
+	}
+
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate message: %w", err)
+	}
+	message := []byte("portpatrol " + hex.EncodeToString(nonce))
+
+	subscribed := false
+	for {
+		packetType, resp, err := readMQTTPacket(r)
+		if errors.Is(err, errMQTTPacketTooLarge) && packetType&0xF0 == mqttPublish {
+			continue // A large retained message or a message of another client
+		}
+		if err != nil {
+			if subscribed {
+				return fmt.Errorf("published message was not received: %w", err)
+			}
+			return fmt.Errorf("failed to read SUBACK: %w", err)
+		}
+
+		switch packetType & 0xF0 {
+		case mqttSubAck:
+			if err := c.checkSubAck(resp, packetID); err != nil {
+				return err
+			}
+			subscribed = true
+
+			// QoS 0 publication, sent after the subscription is active
+			body := appendMQTTString(nil, c.topic)
+			if c.version == MQTTVersion5 {
+				body = append(body, 0) // No properties
+			}
+			body = append(body, message...)
+			if err := writeMQTTPacket(conn, mqttPublish, body); err != nil {
+				return fmt.Errorf("failed to send PUBLISH: %w", err)
+			}
+		case mqttPublish:
+			// Retained messages or messages of other clients are skipped
+			if payload, ok := c.publishPayload(packetType, resp); ok && bytes.Equal(payload, message) {
+				return nil
+			}
+		}
+	}
+}
+
+// checkSubAck verifies that the subscription was granted.
+func (c *MQTTChecker) checkSubAck(resp []byte, packetID uint16) error {
+	if len(resp) < 3 || binary.BigEndian.Uint16(resp) != packetID {
+		return errors.New("invalid SUBACK")
+	}
+
+	codes := resp[2:]
+	if c.version == MQTTVersion5 {
+		size, n, err := readMQTTVarint(bytes.NewReader(codes))
+		if err != nil || n+size >= len(codes) {
+			return errors.New("invalid SUBACK")
+		}
+		codes = codes[n+size:]
+	}
+
+	if codes[0] >= 0x80 {
+		return fmt.Errorf("subscription to %q rejected: %s", c.topic, mqttReasonCode(codes[0]))
+	}
+	return nil
+}
+
+// publishPayload returns the payload of a PUBLISH packet to the configured topic.
+func (c *MQTTChecker) publishPayload(packetType byte, resp []byte) ([]byte, bool) {
+	if len(resp) < 2 {
+		return nil, false
+	}
+	topicLen := int(binary.BigEndian.Uint16(resp))
+	if 2+topicLen > len(resp) || string(resp[2:2+topicLen]) != c.topic {
+		return nil, false
+	}
+	rest := resp[2+topicLen:]
+
+	if qos := (packetType >> 1) & 0x03; qos > 0 {
+		if len(rest) < 2 {
+			return nil, false
+		}
+		rest = rest[2:] // Packet identifier
+	}
+
+	if c.version == MQTTVersion5 {
+		size, n, err := readMQTTVarint(bytes.NewReader(rest))
+		if err != nil || n+size > len(rest) {
+			return nil, false
+		}
+		rest = rest[n+size:]
+	}
+
+	return rest, true
+}
+
+// mqttReasonCode formats an MQTT 5 reason code with its name.
+func mqttReasonCode(code byte) string {
+	if reason, ok := mqttReasonCodes[code]; ok {
+		return fmt.Sprintf("%s (0x%02X)", reason, code)
+	}
+	return fmt.Sprintf("reason code 0x%02X", code)
+}
+
+// appendMQTTString appends a UTF-8 string with a two byte length prefix.
+func appendMQTTString(buf []byte, s string) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(s)))
+	return append(buf, s...)
+}
+
+// writeMQTTPacket writes a control packet with its fixed header.
+func writeMQTTPacket(w io.Writer, packetType byte, body []byte) error {
+	packet := []byte{packetType}
+	for size := len(body); ; {
+		b := byte(size % 128)
+		size /= 128
+		if size > 0 {
+			b |= 0x80
+		}
+		packet = append(packet, b)
+		if size == 0 {
+			break
+		}
+	}
+	packet = append(packet, body...)
+
+	_, err := w.Write(packet)
+	return err
+}
+
+// readMQTTPacket reads a control packet and returns its first byte and the remaining bytes. The body of a packet
+// exceeding maxMQTTPacketSize is discarded and errMQTTPacketTooLarge is returned with the first byte.
+func readMQTTPacket(r *bufio.Reader) (byte, []byte, error) {
+	packetType, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	size, _, err := readMQTTVarint(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if size > maxMQTTPacketSize {
+		if _, err := r.Discard(size); err != nil {
+			return 0, nil, err
+		}
+		return packetType, nil, errMQTTPacketTooLarge
+	}
+
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return packetType, body, nil
+}
+
+// readMQTTVarint reads a variable byte integer and returns its value and encoded length.
+func readMQTTVarint(r io.ByteReader) (int, int, error) {
+	value, multiplier := 0, 1
+	for n := 1; n <= 4; n++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, 0, err
+		}
+		value += int(b&0x7F) * multiplier
+		if b&0x80 == 0 {
+			return value, n, nil
+		}
+		multiplier *= 128
+	}
+	return 0, 0, errors.New("malformed variable byte integer")
+}
+
+// newMQTTChecker creates a new MQTTChecker with functional options.
+func newMQTTChecker(name, address string, opts ...Option) (*MQTTChecker, error) {
+	checker := &MQTTChecker{
+		name:    name,
+		address: address,
+		version: MQTTVersion311,
+		timeout: defaultMQTTTimeout,
+	}
+
+	for _, opt := range opts {
+		opt.apply(checker)
+	}
+
+	return checker, nil
+}
+
+// WithMQTTVersion sets the protocol level, MQTTVersion311 or MQTTVersion5.
+func WithMQTTVersion(version byte) Option {
+	return OptionFunc(func(c Checker) {
+		if mqttChecker, ok := c.(*MQTTChecker); ok {
+			mqttChecker.version = version
+		}
+	})
+}
+
+// WithMQTTClientID sets the client identifier instead of generating one for each check.
+func WithMQTTClientID(clientID string) Option {
+	return OptionFunc(func(c Checker) {
+		if mqttChecker, ok := c.(*MQTTChecker); ok {
+			mqttChecker.clientID = clientID
+		}
+	})
+}
+
+// WithMQTTCredentials sets the user name and password sent in CONNECT.
+func WithMQTTCredentials(username, password string) Option {
+	return OptionFunc(func(c Checker) {
+		if mqttChecker, ok := c.(*MQTTChecker); ok {
+			mqttChecker.username = username
+			mqttChecker.password = password
+		}
+	})
+}
+
+// WithMQTTTLS sets the TLS configuration used for mqtts addresses.
+func WithMQTTTLS(config *tls.Config) Option {
+	return OptionFunc(func(c Checker) {
+		if mqttChecker, ok := c.(*MQTTChecker); ok {
+			mqttChecker.tlsConfig = config
+		}
+	})
+}
+
+// WithMQTTTimeout sets the timeout for the whole check.
+func WithMQTTTimeout(timeout time.Duration) Option {
+	return OptionFunc(func(c Checker) {
+		if mqttChecker, ok := c.(*MQTTChecker); ok {
+			mqttChecker.timeout = timeout
+		}
+	})
+}
+
+// WithMQTTTopic enables the round trip: the checker subscribes to topic and waits for a message it publishes to it.
+func WithMQTTTopic(topic string) Option {
+	return OptionFunc(func(c Checker) {
+		if mqttChecker, ok := c.(*MQTTChecker); ok {
+			mqttChecker.topic = topic
+		}
+	})
+}
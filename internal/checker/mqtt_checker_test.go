@@ -0,0 +1,364 @@
+package checker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMQTTBroker answers CONNECT and echoes publications to subscribed topics.
+type fakeMQTTBroker struct {
+	username string
+	password string
+	versions []byte            // Accepted protocol levels
+	denied   map[string]byte   // SUBACK reason codes by topic
+	retained map[string][]byte // Sent after SUBACK, before any echoed publication
+	noEcho   bool              // Drop publications, like a broker with an ACL denying publishing
+}
+
+// startFakeMQTTBroker serves broker on a local listener, using TLS if tlsConfig is set, and returns its address.
+func startFakeMQTTBroker(t *testing.T, broker fakeMQTTBroker, tlsConfig *tls.Config) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	if tlsConfig != nil {
+		ln = tls.NewListener(ln, tlsConfig)
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go broker.serve(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func (b fakeMQTTBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	var version byte
+	for {
+		packetType, body, err := readMQTTPacket(r)
+		if err != nil {
+			return
+		}
+
+		switch packetType & 0xF0 {
+		case mqttConnect:
+			version = body[6]
+			flags := body[7]
+			if !bytes.Contains(b.versions, []byte{version}) {
+				_ = writeMQTTPacket(conn, mqttConnAck, []byte{0, 1})
+				return
+			}
+
+			rest := body[10:]
+			if version == MQTTVersion5 {
+				rest = rest[1:] // Empty properties
+			}
+			var fields []string
+			for len(rest) >= 2 {
+				n := int(binary.BigEndian.Uint16(rest))
+				fields = append(fields, string(rest[2:2+n]))
+				rest = rest[2+n:]
+			}
+
+			var username, password string
+			fields = fields[1:] // Client identifier
+			if flags&0x80 != 0 {
+				username, fields = fields[0], fields[1:]
+			}
+			if flags&0x40 != 0 {
+				password = fields[0]
+			}
+			if username != b.username || password != b.password {
+				code := byte(4)
+				if version == MQTTVersion5 {
+					code = 0x86
+				}
+				_ = writeMQTTPacket(conn, mqttConnAck, []byte{0, code})
+				return
+			}
+			_ = writeMQTTPacket(conn, mqttConnAck, []byte{0, 0})
+		case mqttSubscribe & 0xF0:
+			rest := body[2:]
+			if version == MQTTVersion5 {
+				rest = rest[1:]
+			}
+			topic := string(rest[2 : 2+binary.BigEndian.Uint16(rest)])
+
+			ack := append([]byte(nil), body[:2]...)
+			if version == MQTTVersion5 {
+				ack = append(ack, 0)
+			}
+			_ = writeMQTTPacket(conn, mqttSubAck, append(ack, b.denied[topic]))
+
+			for retainedTopic, payload := range b.retained {
+				publish := appendMQTTString(nil, retainedTopic)
+				if version == MQTTVersion5 {
+					publish = append(publish, 0)
+				}
+				_ = writeMQTTPacket(conn, mqttPublish|0x01, append(publish, payload...))
+			}
+		case mqttPublish:
+			if !b.noEcho {
+				_ = writeMQTTPacket(conn, packetType, body)
+			}
+		case mqttDisconnect:
+			return
+		}
+	}
+}
+
+func TestNewMQTTChecker(t *testing.T) {
+	t.Parallel()
+
+	checker, err := newMQTTChecker("example", "mqtt://localhost:1883")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "example", checker.Name())
+	assert.Equal(t, "mqtt://localhost:1883", checker.Address())
+	assert.Equal(t, MQTT.String(), checker.Type())
+	assert.Equal(t, MQTTVersion311, checker.version)
+	assert.Equal(t, defaultMQTTTimeout, checker.timeout)
+
+	checker, err = newMQTTChecker("example", "mqtts://localhost:8883",
+		WithMQTTVersion(MQTTVersion5),
+		WithMQTTClientID("probe"),
+		WithMQTTCredentials("device", "secret"),
+		WithMQTTTLS(&tls.Config{ServerName: "mqtt"}),
+		WithMQTTTimeout(3*time.Second),
+		WithMQTTTopic("health/portpatrol"),
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, MQTTVersion5, checker.version)
+	assert.Equal(t, "probe", checker.clientID)
+	assert.Equal(t, "device", checker.username)
+	assert.Equal(t, "secret", checker.password)
+	assert.Equal(t, "mqtt", checker.tlsConfig.ServerName)
+	assert.Equal(t, 3*time.Second, checker.timeout)
+	assert.Equal(t, "health/portpatrol", checker.topic)
+}
+
+func TestMQTTChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	broker := fakeMQTTBroker{
+		username: "device",
+		password: "secret",
+		versions: []byte{MQTTVersion311, MQTTVersion5},
+		denied:   map[string]byte{"admin/health": 0x80},
+	}
+
+	t.Run("Connect", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeMQTTBroker(t, broker, nil)
+		checker, err := newMQTTChecker("mosquitto", "mqtt://"+address, WithMQTTCredentials("device", "secret"))
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Round Trip", func(t *testing.T) {
+		t.Parallel()
+
+		broker := broker
+		broker.retained = map[string][]byte{"health/portpatrol": []byte("stale")}
+		address := startFakeMQTTBroker(t, broker, nil)
+
+		for _, version := range []byte{MQTTVersion311, MQTTVersion5} {
+			checker, err := newMQTTChecker("mosquitto", "mqtt://"+address,
+				WithMQTTVersion(version),
+				WithMQTTCredentials("device", "secret"),
+				WithMQTTTopic("health/portpatrol"),
+			)
+			assert.NoError(t, err)
+
+			assert.NoError(t, checker.Check(context.Background()))
+		}
+	})
+
+	t.Run("TLS", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewUnstartedServer(nil)
+		server.StartTLS()
+		tlsConfig := &tls.Config{Certificates: server.TLS.Certificates}
+		pool := x509.NewCertPool()
+		pool.AddCert(server.Certificate())
+		server.Close()
+
+		address := startFakeMQTTBroker(t, broker, tlsConfig)
+		checker, err := newMQTTChecker("emqx", "mqtts://"+address, WithMQTTCredentials("device", "secret"))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "TLS handshake failed")
+
+		for _, config := range []*tls.Config{{RootCAs: pool}, {InsecureSkipVerify: true}} {
+			checker, err := newMQTTChecker("emqx", "mqtts://"+address,
+				WithMQTTCredentials("device", "secret"),
+				WithMQTTTLS(config),
+			)
+			assert.NoError(t, err)
+
+			assert.NoError(t, checker.Check(context.Background()))
+		}
+	})
+
+	t.Run("Large Retained Message", func(t *testing.T) {
+		t.Parallel()
+
+		broker := broker
+		broker.retained = map[string][]byte{"health/portpatrol": bytes.Repeat([]byte("x"), maxMQTTPacketSize+1)}
+		address := startFakeMQTTBroker(t, broker, nil)
+		checker, err := newMQTTChecker("mosquitto", "mqtt://"+address,
+			WithMQTTCredentials("device", "secret"),
+			WithMQTTTopic("health/portpatrol"),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Password Without Username", func(t *testing.T) {
+		t.Parallel()
+
+		broker := broker
+		broker.username = ""
+		broker.password = "token"
+		address := startFakeMQTTBroker(t, broker, nil)
+
+		checker, err := newMQTTChecker("mosquitto", "mqtt://"+address, WithMQTTCredentials("", "token"))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "a password requires a username with MQTT 3.1.1")
+
+		checker, err = newMQTTChecker("mosquitto", "mqtt://"+address,
+			WithMQTTVersion(MQTTVersion5),
+			WithMQTTCredentials("", "token"),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Bad Credentials", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeMQTTBroker(t, broker, nil)
+		checker, err := newMQTTChecker("mosquitto", "mqtt://"+address, WithMQTTCredentials("device", "wrong"))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "connection refused by broker: bad user name or password (4)")
+
+		checker, err = newMQTTChecker("mosquitto", "mqtt://"+address, WithMQTTVersion(MQTTVersion5))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "connection refused by broker: bad user name or password (0x86)")
+	})
+
+	t.Run("Unsupported Protocol Version", func(t *testing.T) {
+		t.Parallel()
+
+		broker := broker
+		broker.versions = []byte{MQTTVersion311}
+		address := startFakeMQTTBroker(t, broker, nil)
+		checker, err := newMQTTChecker("mosquitto", "mqtt://"+address, WithMQTTVersion(MQTTVersion5))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "connection refused by broker: unacceptable protocol version (1)")
+	})
+
+	t.Run("Subscription Rejected", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeMQTTBroker(t, broker, nil)
+		checker, err := newMQTTChecker("mosquitto", "mqtt://"+address,
+			WithMQTTCredentials("device", "secret"),
+			WithMQTTTopic("admin/health"),
+		)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, `subscription to "admin/health" rejected: unspecified error (0x80)`)
+	})
+
+	t.Run("Message Not Received", func(t *testing.T) {
+		t.Parallel()
+
+		broker := broker
+		broker.noEcho = true
+		address := startFakeMQTTBroker(t, broker, nil)
+		checker, err := newMQTTChecker("mosquitto", "mqtt://"+address,
+			WithMQTTCredentials("device", "secret"),
+			WithMQTTTopic("health/portpatrol"),
+			WithMQTTTimeout(100*time.Millisecond),
+		)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "published message was not received")
+		assert.Contains(t, err.Error(), "i/o timeout")
+	})
+
+	t.Run("Unsupported Scheme", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newMQTTChecker("mosquitto", "http://localhost:1883")
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, `unsupported scheme "http": must be mqtt or mqtts`)
+	})
+
+	t.Run("No Reply", func(t *testing.T) {
+		t.Parallel()
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		t.Cleanup(func() { _ = ln.Close() })
+		go func() {
+			conn, err := ln.Accept()
+			if err == nil {
+				_, _ = io.Copy(io.Discard, conn) // Never replies, returns once the checker closes the connection
+				_ = conn.Close()
+			}
+		}()
+
+		checker, err := newMQTTChecker("mosquitto", "mqtt://"+ln.Addr().String(), WithMQTTTimeout(100*time.Millisecond))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "i/o timeout")
+	})
+}
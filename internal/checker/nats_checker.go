@@ -0,0 +1,424 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultNATSTimeout time.Duration = 2 * time.Second
+	maxNATSLineSize    int           = 64 * 1024   // INFO lines list the URLs of the cluster and grow with it
+	maxNATSPayloadSize int           = 1024 * 1024 // Default max_payload of the server
+)
+
+// NATSChecker implements the Checker interface by connecting to a NATS server and exchanging PING/PONG.
+type NATSChecker struct {
+	name       string
+	address    string
+	timeout    time.Duration // Timeout for connecting and the whole check
+	username   string        // Sent in CONNECT if set
+	password   string
+	token      string      // Sent in CONNECT if set
+	jetStream  bool        // Require JetStream to be available for the account
+	stream     string      // Stream that must exist, implies jetStream
+	tlsConfig  *tls.Config // Upgrades the connection after INFO if set
+	lastInfo   *natsInfo
+	lastStream *natsStreamInfo
+}
+
+// natsInfo holds the fields of the INFO line used by the checker.
+type natsInfo struct {
+	ServerName   string `json:"server_name"`
+	Version      string `json:"version"`
+	TLSRequired  bool   `json:"tls_required"`
+	TLSAvailable bool   `json:"tls_available"`
+	JetStream    bool   `json:"jetstream"`
+}
+
+// natsConnect holds the options sent in CONNECT.
+type natsConnect struct {
+	Verbose      bool   `json:"verbose"`
+	Pedantic     bool   `json:"pedantic"`
+	TLSRequired  bool   `json:"tls_required"`
+	Name         string `json:"name"`
+	Lang         string `json:"lang"`
+	Version      string `json:"version"`
+	Protocol     int    `json:"protocol"`
+	Headers      bool   `json:"headers"`
+	NoResponders bool   `json:"no_responders"` // Requests without subscribers are answered with status 503
+	User         string `json:"user,omitempty"`
+	Pass         string `json:"pass,omitempty"`
+	AuthToken    string `json:"auth_token,omitempty"`
+}
+
+// natsAPIError is the error of a JetStream API response.
+type natsAPIError struct {
+	Code        int    `json:"code"`
+	ErrCode     int    `json:"err_code"`
+	Description string `json:"description"`
+}
+
+func (e *natsAPIError) Error() string {
+	if e.ErrCode != 0 {
+		return fmt.Sprintf("%s (%d)", e.Description, e.ErrCode)
+	}
+	return e.Description
+}
+
+// natsStreamInfo holds the fields of a stream info response used by the checker.
+type natsStreamInfo struct {
+	Error *natsAPIError `json:"error"`
+	State struct {
+		Messages uint64 `json:"messages"`
+	} `json:"state"`
+}
+
+func (c *NATSChecker) Address() string { return c.address }
+func (c *NATSChecker) Name() string    { return c.name }
+func (c *NATSChecker) Type() string    { return NATS.String() }
+
+// Details returns the server name and version reported in the last check, and the number of messages in the stream.
+func (c *NATSChecker) Details() []slog.Attr {
+	if c.lastInfo == nil {
+		return nil
+	}
+
+	attrs := []slog.Attr{
+		slog.String("server_name", c.lastInfo.ServerName),
+		slog.String("version", c.lastInfo.Version),
+	}
+	if c.lastStream != nil {
+		attrs = append(attrs, slog.Uint64("messages", c.lastStream.State.Messages))
+	}
+	return attrs
+}
+
+func (c *NATSChecker) Check(ctx context.Context) error {
+	c.lastInfo = nil
+	c.lastStream = nil
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	address := strings.TrimPrefix(c.address, "nats://")
+	conn, err := dialTCP(ctx, address, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }() // conn is replaced by the TLS connection after the upgrade
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("failed to set deadline: %w", err)
+	}
+
+	r := bufio.NewReaderSize(conn, maxNATSLineSize)
+	info, err := readNATSInfo(r)
+	if err != nil {
+		return err
+	}
+	c.lastInfo = info
+
+	// NATS servers send INFO in plain text and expect the client to start the TLS handshake afterwards
+	if c.tlsConfig != nil {
+		if !info.TLSRequired && !info.TLSAvailable {
+			return errors.New("server does not support TLS")
+		}
+		tlsConn := tls.Client(conn, clientTLSConfig(address, c.tlsConfig))
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return fmt.Errorf("TLS handshake failed: %w", err)
+		}
+		conn = tlsConn
+		r = bufio.NewReaderSize(conn, maxNATSLineSize)
+	} else if info.TLSRequired {
+		return errors.New("server requires TLS")
+	}
+
+	if err := c.connect(conn, r); err != nil {
+		return err
+	}
+
+	if c.jetStream || c.stream != "" {
+		if !info.JetStream {
+			return errors.New("JetStream is not enabled on the server")
+		}
+		if err := c.checkJetStream(conn, r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// connect sends CONNECT followed by PING and waits for PONG.
+func (c *NATSChecker) connect(conn net.Conn, r *bufio.Reader) error {
+	options, err := json.Marshal(natsConnect{
+		TLSRequired:  c.tlsConfig != nil,
+		Name:         "portpatrol",
+		Lang:         "go",
+		Protocol:     1,
+		Headers:      true,
+		NoResponders: true,
+		User:         c.username,
+		Pass:         c.password,
+		AuthToken:    c.token,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode CONNECT: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", options); err != nil {
+		return fmt.Errorf("failed to send CONNECT: %w", err)
+	}
+
+	op, args, err := readNATSOp(conn, r)
+	if err != nil {
+		return fmt.Errorf("failed to read PONG: %w", err)
+	}
+
+	switch op {
+	case "PONG":
+		return nil
+	case "-ERR":
+		return fmt.Errorf("connection rejected: %s", strings.Trim(args, "'"))
+	default:
+		return fmt.Errorf("unexpected %s, expected PONG", op)
+	}
+}
+
+// checkJetStream requests the account information and, if configured, the stream information from the JetStream API.
+func (c *NATSChecker) checkJetStream(conn net.Conn, r *bufio.Reader) error {
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate inbox: %w", err)
+	}
+	inbox := "_INBOX." + hex.EncodeToString(nonce)
+
+	if _, err := fmt.Fprintf(conn, "SUB %s.* 1\r\n", inbox); err != nil {
+		return fmt.Errorf("failed to send SUB: %w", err)
+	}
+
+	var account struct {
+		Error *natsAPIError `json:"error"`
+	}
+	if err := natsRequest(conn, r, "$JS.API.INFO", inbox+".1", &account); err != nil {
+		return fmt.Errorf("JetStream is not available: %w", err)
+	}
+	if account.Error != nil {
+		return fmt.Errorf("JetStream is not available: %w", account.Error)
+	}
+
+	if c.stream == "" {
+		return nil
+	}
+
+	var stream natsStreamInfo
+	if err := natsRequest(conn, r, "$JS.API.STREAM.INFO."+c.stream, inbox+".2", &stream); err != nil {
+		return fmt.Errorf("stream %q is not available: %w", c.stream, err)
+	}
+	if stream.Error != nil {
+		return fmt.Errorf("stream %q is not available: %w", c.stream, stream.Error)
+	}
+	c.lastStream = &stream
+
+	return nil
+}
+
+// natsRequest publishes an empty message to subject and decodes the JSON reply received on the reply subject into v.
+func natsRequest(conn net.Conn, r *bufio.Reader, subject, reply string, v any) error {
+	if _, err := fmt.Fprintf(conn, "PUB %s %s 0\r\n\r\n", subject, reply); err != nil {
+		return fmt.Errorf("failed to send PUB: %w", err)
+	}
+
+	for {
+		op, args, err := readNATSOp(conn, r)
+		if err != nil {
+			return err
+		}
+
+		var fields []string
+		var headerSize, size int
+		switch op {
+		case "MSG": // MSG <subject> <sid> [reply-to] <#bytes>
+			fields = strings.Fields(args)
+			if len(fields) < 3 {
+				return fmt.Errorf("invalid MSG: %q", args)
+			}
+			size, err = strconv.Atoi(fields[len(fields)-1])
+		case "HMSG": // HMSG <subject> <sid> [reply-to] <#header bytes> <#total bytes>
+			fields = strings.Fields(args)
+			if len(fields) < 4 {
+				return fmt.Errorf("invalid HMSG: %q", args)
+			}
+			if headerSize, err = strconv.Atoi(fields[len(fields)-2]); err == nil {
+				size, err = strconv.Atoi(fields[len(fields)-1])
+			}
+		case "-ERR":
+			return fmt.Errorf("server error: %s", strings.Trim(args, "'"))
+		default:
+			return fmt.Errorf("unexpected %s", op)
+		}
+		if err != nil || size < headerSize || size > maxNATSPayloadSize {
+			return fmt.Errorf("invalid %s: %q", op, args)
+		}
+
+		payload := make([]byte, size+2) // Trailing CRLF
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return err
+		}
+		if fields[0] != reply {
+			continue
+		}
+
+		// Headers start with a status line like "NATS/1.0 503", which is sent if no service answers the request
+		if headerSize > 0 {
+			status, _, _ := strings.Cut(string(payload[:headerSize]), "\r\n")
+			if code := strings.TrimSpace(strings.TrimPrefix(status, "NATS/1.0")); code != "" {
+				if strings.HasPrefix(code, "503") {
+					return errors.New("no responders")
+				}
+				return fmt.Errorf("unexpected status %q", code)
+			}
+		}
+
+		if err := json.Unmarshal(payload[headerSize:size], v); err != nil {
+			return fmt.Errorf("invalid response: %w", err)
+		}
+		return nil
+	}
+}
+
+// readNATSInfo reads the INFO line sent by the server after accepting the connection.
+func readNATSInfo(r *bufio.Reader) (*natsInfo, error) {
+	line, err := readNATSLine(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read INFO: %w", err)
+	}
+
+	op, args, _ := strings.Cut(line, " ")
+	if !strings.EqualFold(op, "INFO") {
+		return nil, errors.New("server did not send INFO")
+	}
+
+	var info natsInfo
+	if err := json.Unmarshal([]byte(args), &info); err != nil {
+		return nil, fmt.Errorf("invalid INFO: %w", err)
+	}
+	return &info, nil
+}
+
+// readNATSOp reads the next protocol operation and its arguments. PING is answered and INFO and +OK are skipped.
+func readNATSOp(conn net.Conn, r *bufio.Reader) (string, string, error) {
+	for {
+		line, err := readNATSLine(r)
+		if err != nil {
+			return "", "", err
+		}
+
+		op, args, _ := strings.Cut(line, " ")
+		switch op = strings.ToUpper(op); op {
+		case "PING":
+			if _, err := conn.Write([]byte("PONG\r\n")); err != nil {
+				return "", "", err
+			}
+		case "INFO", "+OK":
+			// Cluster updates and acknowledgements are not relevant
+		default:
+			return op, strings.TrimSpace(args), nil
+		}
+	}
+}
+
+// readNATSLine reads a CRLF terminated protocol line.
+func readNATSLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		return "", fmt.Errorf("line exceeds %d bytes", maxNATSLineSize)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+// newNATSChecker creates a new NATSChecker with functional options.
+func newNATSChecker(name, address string, opts ...Option) (*NATSChecker, error) {
+	checker := &NATSChecker{
+		name:    name,
+		address: address,
+		timeout: defaultNATSTimeout,
+	}
+
+	for _, opt := range opts {
+		opt.apply(checker)
+	}
+
+	return checker, nil
+}
+
+// WithNATSTimeout sets the timeout for connecting and the whole check.
+func WithNATSTimeout(timeout time.Duration) Option {
+	return OptionFunc(func(c Checker) {
+		if natsChecker, ok := c.(*NATSChecker); ok {
+			natsChecker.timeout = timeout
+		}
+	})
+}
+
+// WithNATSCredentials sets the user and password sent in CONNECT.
+func WithNATSCredentials(username, password string) Option {
+	return OptionFunc(func(c Checker) {
+		if natsChecker, ok := c.(*NATSChecker); ok {
+			natsChecker.username = username
+			natsChecker.password = password
+		}
+	})
+}
+
+// WithNATSToken sets the authentication token sent in CONNECT.
+func WithNATSToken(token string) Option {
+	return OptionFunc(func(c Checker) {
+		if natsChecker, ok := c.(*NATSChecker); ok {
+			natsChecker.token = token
+		}
+	})
+}
+
+// WithNATSJetStream requires JetStream to be enabled and available for the account.
+func WithNATSJetStream(enabled bool) Option {
+	return OptionFunc(func(c Checker) {
+		if natsChecker, ok := c.(*NATSChecker); ok {
+			natsChecker.jetStream = enabled
+		}
+	})
+}
+
+// WithNATSStream requires the JetStream stream to exist.
+func WithNATSStream(stream string) Option {
+	return OptionFunc(func(c Checker) {
+		if natsChecker, ok := c.(*NATSChecker); ok {
+			natsChecker.stream = stream
+		}
+	})
+}
+
+// WithNATSTLS upgrades the connection to TLS after the server sent INFO.
+func WithNATSTLS(config *tls.Config) Option {
+	return OptionFunc(func(c Checker) {
+		if natsChecker, ok := c.(*NATSChecker); ok {
+			natsChecker.tlsConfig = config
+		}
+	})
+}
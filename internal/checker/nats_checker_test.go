@@ -0,0 +1,315 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeNATSServer speaks the NATS client protocol and answers JetStream API requests.
+type fakeNATSServer struct {
+	user       string
+	pass       string
+	jetStream  string            // "" disables JetStream, "server" enables it without responders, "account" answers the API
+	streams    map[string]uint64 // Message counts by stream
+	tlsConfig  *tls.Config       // Requires the client to upgrade to TLS after INFO if set
+	clusterMsg bool              // Sends an INFO update and a PING before each reply, like a server joining a cluster
+}
+
+// startFakeNATSServer serves server on a local listener and returns its address.
+func startFakeNATSServer(t *testing.T, server fakeNATSServer) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func (s fakeNATSServer) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	info, _ := json.Marshal(map[string]any{
+		"server_id":     "NCXL6YVQ",
+		"server_name":   "nats-0",
+		"version":       "2.10.22",
+		"headers":       true,
+		"auth_required": s.user != "",
+		"tls_required":  s.tlsConfig != nil,
+		"jetstream":     s.jetStream != "",
+	})
+	_, _ = fmt.Fprintf(conn, "INFO %s\r\n", info)
+
+	if s.tlsConfig != nil {
+		conn = tls.Server(conn, s.tlsConfig)
+	}
+	r := bufio.NewReader(conn)
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		op, args, _ := strings.Cut(strings.TrimSpace(line), " ")
+
+		switch op {
+		case "CONNECT":
+			var options natsConnect
+			_ = json.Unmarshal([]byte(args), &options)
+			if options.User != s.user || options.Pass != s.pass {
+				_, _ = conn.Write([]byte("-ERR 'Authorization Violation'\r\n"))
+				return
+			}
+		case "PING":
+			_, _ = conn.Write([]byte("PONG\r\n"))
+		case "PUB":
+			fields := strings.Fields(args)
+			size, _ := strconv.Atoi(fields[len(fields)-1])
+			if _, err := io.ReadFull(r, make([]byte, size+2)); err != nil {
+				return
+			}
+			s.reply(conn, r, fields[0], fields[1])
+		}
+	}
+}
+
+// reply answers a request to subject on the reply subject.
+func (s fakeNATSServer) reply(conn net.Conn, r *bufio.Reader, subject, reply string) {
+	if s.clusterMsg {
+		_, _ = conn.Write([]byte("INFO {\"connect_urls\":[\"10.0.0.2:4222\"]}\r\nPING\r\n"))
+		if line, _ := r.ReadString('\n'); line != "PONG\r\n" {
+			return
+		}
+	}
+
+	if s.jetStream != "account" {
+		_, _ = fmt.Fprintf(conn, "HMSG %s 1 16 16\r\nNATS/1.0 503\r\n\r\n\r\n", reply)
+		return
+	}
+
+	var payload string
+	switch stream := strings.TrimPrefix(subject, "$JS.API.STREAM.INFO."); {
+	case subject == "$JS.API.INFO":
+		payload = `{"type":"io.nats.jetstream.api.v1.account_info_response","streams":1}`
+	case stream != subject:
+		messages, ok := s.streams[stream]
+		if !ok {
+			payload = `{"type":"io.nats.jetstream.api.v1.stream_info_response","error":{"code":404,"err_code":10059,"description":"stream not found"}}`
+			break
+		}
+		payload = fmt.Sprintf(`{"type":"io.nats.jetstream.api.v1.stream_info_response","config":{"name":%q},"state":{"messages":%d}}`, stream, messages)
+	}
+
+	_, _ = fmt.Fprintf(conn, "MSG %s 1 %d\r\n%s\r\n", reply, len(payload), payload)
+}
+
+func TestNewNATSChecker(t *testing.T) {
+	t.Parallel()
+
+	checker, err := newNATSChecker("example", "localhost:4222")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "example", checker.Name())
+	assert.Equal(t, "localhost:4222", checker.Address())
+	assert.Equal(t, NATS.String(), checker.Type())
+	assert.Equal(t, defaultNATSTimeout, checker.timeout)
+
+	tlsConfig := &tls.Config{ServerName: "nats"}
+	checker, err = newNATSChecker("example", "nats://localhost:4222",
+		WithNATSTimeout(3*time.Second),
+		WithNATSCredentials("app", "secret"),
+		WithNATSToken("s3cr3t"),
+		WithNATSJetStream(true),
+		WithNATSStream("ORDERS"),
+		WithNATSTLS(tlsConfig),
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3*time.Second, checker.timeout)
+	assert.Equal(t, "app", checker.username)
+	assert.Equal(t, "secret", checker.password)
+	assert.Equal(t, "s3cr3t", checker.token)
+	assert.True(t, checker.jetStream)
+	assert.Equal(t, "ORDERS", checker.stream)
+	assert.Equal(t, tlsConfig, checker.tlsConfig)
+}
+
+func TestNATSChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	server := fakeNATSServer{
+		user:      "app",
+		pass:      "secret",
+		jetStream: "account",
+		streams:   map[string]uint64{"ORDERS": 42},
+	}
+
+	t.Run("Ping Pong", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeNATSServer(t, server)
+		checker, err := newNATSChecker("nats", "nats://"+address, WithNATSCredentials("app", "secret"))
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+		assert.Equal(t, "nats-0", checker.Details()[0].Value.String())
+		assert.Equal(t, "2.10.22", checker.Details()[1].Value.String())
+	})
+
+	t.Run("Stream Exists", func(t *testing.T) {
+		t.Parallel()
+
+		server := server
+		server.clusterMsg = true
+		address := startFakeNATSServer(t, server)
+		checker, err := newNATSChecker("nats", address,
+			WithNATSCredentials("app", "secret"),
+			WithNATSStream("ORDERS"),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+		assert.Equal(t, uint64(42), checker.Details()[2].Value.Uint64())
+	})
+
+	t.Run("Stream Missing", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeNATSServer(t, server)
+		checker, err := newNATSChecker("nats", address, WithNATSCredentials("app", "secret"), WithNATSStream("EVENTS"))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, `stream "EVENTS" is not available: stream not found (10059)`)
+	})
+
+	t.Run("JetStream Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		server := server
+		server.jetStream = ""
+		address := startFakeNATSServer(t, server)
+		checker, err := newNATSChecker("nats", address, WithNATSCredentials("app", "secret"), WithNATSJetStream(true))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "JetStream is not enabled on the server")
+	})
+
+	t.Run("JetStream Not Ready", func(t *testing.T) {
+		t.Parallel()
+
+		server := server
+		server.jetStream = "server"
+		address := startFakeNATSServer(t, server)
+		checker, err := newNATSChecker("nats", address, WithNATSCredentials("app", "secret"), WithNATSJetStream(true))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "JetStream is not available: no responders")
+	})
+
+	t.Run("Authorization Violation", func(t *testing.T) {
+		t.Parallel()
+
+		address := startFakeNATSServer(t, server)
+		checker, err := newNATSChecker("nats", address, WithNATSCredentials("app", "wrong"))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "connection rejected: Authorization Violation")
+	})
+
+	t.Run("TLS", func(t *testing.T) {
+		t.Parallel()
+
+		httpServer := httptest.NewUnstartedServer(nil)
+		httpServer.StartTLS()
+		server := server
+		server.tlsConfig = &tls.Config{Certificates: httpServer.TLS.Certificates}
+		httpServer.Close()
+
+		address := startFakeNATSServer(t, server)
+		checker, err := newNATSChecker("nats", address, WithNATSCredentials("app", "secret"))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "server requires TLS")
+
+		checker, err = newNATSChecker("nats", address,
+			WithNATSCredentials("app", "secret"),
+			WithNATSTLS(&tls.Config{InsecureSkipVerify: true}),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Not A NATS Server", func(t *testing.T) {
+		t.Parallel()
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		t.Cleanup(func() { _ = ln.Close() })
+		go func() {
+			conn, err := ln.Accept()
+			if err == nil {
+				_, _ = conn.Write([]byte("220 mail.example.com ESMTP\r\n"))
+				_ = conn.Close()
+			}
+		}()
+
+		checker, err := newNATSChecker("nats", ln.Addr().String())
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "server did not send INFO")
+		assert.Nil(t, checker.Details())
+	})
+
+	t.Run("No Reply", func(t *testing.T) {
+		t.Parallel()
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		t.Cleanup(func() { _ = ln.Close() })
+		go func() {
+			conn, err := ln.Accept()
+			if err == nil {
+				_, _ = io.Copy(io.Discard, conn) // Never replies, returns once the checker closes the connection
+				_ = conn.Close()
+			}
+		}()
+
+		checker, err := newNATSChecker("nats", ln.Addr().String(), WithNATSTimeout(100*time.Millisecond))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "i/o timeout")
+	})
+}
@@ -0,0 +1,315 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTokenServer starts a token endpoint issuing numbered tokens with the given lifetime in seconds.
+func newTokenServer(t *testing.T, expiresIn int, requests *int32) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(tokenHandler(expiresIn, requests))
+}
+
+// tokenHandler answers client credentials grants with numbered tokens with the given lifetime in seconds.
+func tokenHandler(expiresIn int, requests *int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "client" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		n := atomic.AddInt32(requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d,"scope":%q}`, n, expiresIn, r.Form.Get("scope"))
+	})
+}
+
+func TestOAuth2TokenSource(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Token is cached", func(t *testing.T) {
+		t.Parallel()
+
+		var requests int32
+		server := newTokenServer(t, 3600, &requests)
+		defer server.Close()
+
+		source := &oauth2TokenSource{tokenURL: server.URL, clientID: "client", clientSecret: "secret", client: server.Client()}
+
+		token, err := source.Token(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "token-1", token)
+
+		token, err = source.Token(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "token-1", token)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("Expired token is refreshed", func(t *testing.T) {
+		t.Parallel()
+
+		var requests int32
+		server := newTokenServer(t, 1, &requests) // shorter than oauth2ExpiryDelta
+		defer server.Close()
+
+		source := &oauth2TokenSource{tokenURL: server.URL, clientID: "client", clientSecret: "secret", client: server.Client()}
+
+		token, err := source.Token(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "token-1", token)
+
+		token, err = source.Token(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "token-2", token)
+	})
+
+	t.Run("Invalidated token is refreshed", func(t *testing.T) {
+		t.Parallel()
+
+		var requests int32
+		server := newTokenServer(t, 3600, &requests)
+		defer server.Close()
+
+		source := &oauth2TokenSource{tokenURL: server.URL, clientID: "client", clientSecret: "secret", client: server.Client()}
+
+		_, err := source.Token(context.Background())
+		assert.NoError(t, err)
+
+		source.Invalidate()
+
+		token, err := source.Token(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "token-2", token)
+	})
+
+	t.Run("Invalid client credentials", func(t *testing.T) {
+		t.Parallel()
+
+		var requests int32
+		server := newTokenServer(t, 3600, &requests)
+		defer server.Close()
+
+		source := &oauth2TokenSource{tokenURL: server.URL, clientID: "client", clientSecret: "wrong", client: server.Client()}
+
+		_, err := source.Token(context.Background())
+		assert.EqualError(t, err, "token endpoint returned status 401")
+	})
+
+	t.Run("Missing access token", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"token_type":"Bearer"}`)
+		}))
+		defer server.Close()
+
+		source := &oauth2TokenSource{tokenURL: server.URL, client: server.Client()}
+
+		_, err := source.Token(context.Background())
+		assert.EqualError(t, err, "token response does not contain an access token")
+	})
+}
+
+func TestHTTPCheckerAuthentication(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Basic authentication", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, password, ok := r.BasicAuth()
+			if !ok || user != "admin" || password != "pa:ss" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		checker, err := newHTTPChecker("example", server.URL, WithHTTPBasicAuth("admin", "pa:ss"))
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		assert.NoError(t, checker.Check(ctx))
+	})
+
+	t.Run("OAuth2 client credentials", func(t *testing.T) {
+		t.Parallel()
+
+		var requests int32
+		tokenServer := newTokenServer(t, 3600, &requests)
+		defer tokenServer.Close()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		checker, err := newHTTPChecker("example", server.URL,
+			WithHTTPOAuth2ClientCredentials(tokenServer.URL, "client", "secret", []string{"health:read"}),
+		)
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		assert.NoError(t, checker.Check(ctx))
+		assert.NoError(t, checker.Check(ctx))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("OAuth2 token is refreshed after unauthorized response", func(t *testing.T) {
+		t.Parallel()
+
+		var requests int32
+		tokenServer := newTokenServer(t, 3600, &requests)
+		defer tokenServer.Close()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		checker, err := newHTTPChecker("example", server.URL,
+			WithHTTPOAuth2ClientCredentials(tokenServer.URL, "client", "secret", nil),
+		)
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		assert.EqualError(t, checker.Check(ctx), "unexpected status code: got 401, expected one of [200]")
+		assert.NoError(t, checker.Check(ctx))
+	})
+
+	t.Run("OAuth2 token is fetched from the token URL for a unix socket target", func(t *testing.T) {
+		t.Parallel()
+
+		var requests int32
+		tokenServer := newTokenServer(t, 3600, &requests)
+		defer tokenServer.Close()
+
+		path := filepath.Join(t.TempDir(), "app.sock")
+		ln, err := net.Listen("unix", path)
+		assert.NoError(t, err)
+		server := &http.Server{
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer token-1" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}),
+		}
+		go func() { _ = server.Serve(ln) }()
+		defer server.Close()
+
+		checker, err := newHTTPChecker("envoy", "unix://"+path+":/healthz",
+			WithHTTPOAuth2ClientCredentials(tokenServer.URL, "client", "secret", nil),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("OAuth2 token request ignores the resolve overrides of the target", func(t *testing.T) {
+		t.Parallel()
+
+		var requests int32
+		tokenServer := newTokenServer(t, 3600, &requests)
+		defer tokenServer.Close()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		// Nothing listens on 127.0.0.2, so the token request fails if it is rerouted
+		_, tokenPort, _ := net.SplitHostPort(tokenServer.Listener.Addr().String())
+		checker, err := newHTTPChecker("example", server.URL,
+			WithHTTPResolve(map[string]string{"127.0.0.1:" + tokenPort: "127.0.0.2"}),
+			WithHTTPOAuth2ClientCredentials(tokenServer.URL, "client", "secret", nil),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("OAuth2 token endpoint certificate is verified when the target's is not", func(t *testing.T) {
+		t.Parallel()
+
+		var requests int32
+		tokenServer := httptest.NewTLSServer(tokenHandler(3600, &requests))
+		defer tokenServer.Close()
+
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		checker, err := newHTTPChecker("example", server.URL,
+			WithHTTPSkipTLSVerify(true),
+			WithHTTPOAuth2ClientCredentials(tokenServer.URL, "client", "secret", nil),
+		)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "certificate")
+		assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+
+		checker, err = newHTTPChecker("example", server.URL,
+			WithHTTPSkipTLSVerify(true),
+			WithHTTPOAuth2ClientCredentials(tokenServer.URL, "client", "secret", nil),
+			WithHTTPOAuth2SkipTLSVerify(true),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("OAuth2 token endpoint failure", func(t *testing.T) {
+		t.Parallel()
+
+		var requests int32
+		tokenServer := newTokenServer(t, 3600, &requests)
+		defer tokenServer.Close()
+
+		checker, err := newHTTPChecker("example", "http://127.0.0.1:1",
+			WithHTTPOAuth2ClientCredentials(tokenServer.URL, "client", "wrong", nil),
+		)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "failed to obtain OAuth2 token: token endpoint returned status 401")
+	})
+}
@@ -0,0 +1,56 @@
+package checker
+
+import (
+	"context"
+	"net"
+	"strings"
+)
+
+// dialContextFunc is the signature of net.Dialer.DialContext.
+type dialContextFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// dialOverrideKey is the context key for a dial override that applies to a single check.
+type dialOverrideKey struct{}
+
+// dialOverride forces connections to address to be made to ip.
+type dialOverride struct {
+	address string
+	ip      string
+}
+
+// withDialOverride returns a context that makes dial functions created by overrideDialContext connect to ip
+// when dialing the "host:port" address.
+func withDialOverride(ctx context.Context, address, ip string) context.Context {
+	return context.WithValue(ctx, dialOverrideKey{}, dialOverride{address: normalizeHostPort(address), ip: ip})
+}
+
+// overrideDialContext returns a dial function that connects to the IP from overrides instead of resolving
+// the host, if the "host:port" address has an override. Other addresses are dialed unchanged.
+// Since only the dialed IP changes, the Host header and the TLS server name remain the original host.
+func overrideDialContext(dialer *net.Dialer, overrides map[string]string) dialContextFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if host, port, err := net.SplitHostPort(address); err == nil {
+			if o, ok := ctx.Value(dialOverrideKey{}).(dialOverride); ok && o.address == normalizeHostPort(address) {
+				address = net.JoinHostPort(o.ip, port)
+			} else if ip, ok := resolveOverride(overrides, host, port); ok {
+				address = net.JoinHostPort(ip, port)
+			}
+		}
+		return dialer.DialContext(ctx, network, address)
+	}
+}
+
+// resolveOverride returns the IP configured for host and port in overrides.
+func resolveOverride(overrides map[string]string, host, port string) (string, bool) {
+	ip, ok := overrides[net.JoinHostPort(strings.ToLower(host), port)]
+	return ip, ok
+}
+
+// normalizeHostPort lowercases the host of a "host:port" address.
+func normalizeHostPort(address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	return net.JoinHostPort(strings.ToLower(host), port)
+}
@@ -0,0 +1,53 @@
+package checker
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+)
+
+// maxReportedBytes is the number of bytes of an unexpected response included in errors.
+const maxReportedBytes int = 256
+
+// responseMatcher checks a response against an expected literal or pattern.
+type responseMatcher struct {
+	expect      []byte         // Literal the response must contain
+	expectRegex *regexp.Regexp // Pattern the response must match
+}
+
+// configured reports whether a response is expected at all.
+func (m responseMatcher) configured() bool {
+	return len(m.expect) > 0 || m.expectRegex != nil
+}
+
+// matches reports whether the response satisfies the expectation.
+func (m responseMatcher) matches(received []byte) bool {
+	if m.expectRegex != nil {
+		return m.expectRegex.Match(received)
+	}
+	return bytes.Contains(received, m.expect)
+}
+
+// expectation describes the expected response for error messages.
+func (m responseMatcher) expectation() string {
+	if m.expectRegex != nil {
+		return fmt.Sprintf("expected pattern %q", m.expectRegex.String())
+	}
+	return fmt.Sprintf("expected %q", m.expect)
+}
+
+// mismatch returns the error for a response that does not satisfy the expectation, wrapping err if it is not nil.
+func (m responseMatcher) mismatch(received []byte, err error) error {
+	if err != nil {
+		return fmt.Errorf("unexpected response, %s: received %d bytes %s: %w", m.expectation(), len(received), quoteBytes(received), err)
+	}
+	return fmt.Errorf("unexpected response, %s: received %d bytes %s", m.expectation(), len(received), quoteBytes(received))
+}
+
+// quoteBytes quotes data for error messages, truncated to maxReportedBytes.
+func quoteBytes(data []byte) string {
+	if len(data) > maxReportedBytes {
+		return fmt.Sprintf("%q...", data[:maxReportedBytes])
+	}
+	return fmt.Sprintf("%q", data)
+}
@@ -0,0 +1,148 @@
+package checker
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+// maxSCRAMIterations bounds the iteration count a server can demand. The key derivation does not observe the
+// check's context, so a huge count would keep the CPU busy long after the timeout. Real servers use at most a
+// few hundred thousand iterations.
+const maxSCRAMIterations int = 1_000_000
+
+// scramClient implements the client side of a SCRAM conversation (RFC 5802) without channel binding.
+// The password is used as is, SASLprep normalization is not applied.
+type scramClient struct {
+	hash            func() hash.Hash
+	username        string
+	password        string
+	nonce           string
+	clientFirstBare string
+	serverSignature []byte
+}
+
+// newSCRAMClient creates a SCRAM client using the given hash function and client nonce.
+func newSCRAMClient(h func() hash.Hash, username, password, nonce string) *scramClient {
+	return &scramClient{
+		hash:            h,
+		username:        username,
+		password:        password,
+		nonce:           nonce,
+		clientFirstBare: "n=" + scramEscape(username) + ",r=" + nonce,
+	}
+}
+
+// clientFirst returns the client-first-message.
+func (s *scramClient) clientFirst() string {
+	return "n,," + s.clientFirstBare
+}
+
+// clientFinal computes the client-final-message containing the proof for the server-first-message.
+func (s *scramClient) clientFinal(serverFirst string) (string, error) {
+	attrs := parseSCRAMAttributes(serverFirst)
+	if message, ok := attrs["e"]; ok {
+		return "", fmt.Errorf("server error: %s", message)
+	}
+
+	serverNonce := attrs["r"]
+	if !strings.HasPrefix(serverNonce, s.nonce) || len(serverNonce) == len(s.nonce) {
+		return "", errors.New("invalid server nonce")
+	}
+	salt, err := base64.StdEncoding.DecodeString(attrs["s"])
+	if err != nil || len(salt) == 0 {
+		return "", errors.New("invalid salt")
+	}
+	iterations, err := strconv.Atoi(attrs["i"])
+	if err != nil || iterations < 1 {
+		return "", fmt.Errorf("invalid iteration count: %q", attrs["i"])
+	}
+	if iterations > maxSCRAMIterations {
+		return "", fmt.Errorf("iteration count %d exceeds the maximum of %d", iterations, maxSCRAMIterations)
+	}
+
+	saltedPassword := pbkdf2Key(s.hash, []byte(s.password), salt, iterations, s.hash().Size())
+	clientKey := hmacSum(s.hash, saltedPassword, "Client Key")
+	storedKey := s.hash()
+	storedKey.Write(clientKey)
+
+	withoutProof := "c=biws,r=" + serverNonce // "biws" is the base64 encoded GS2 header "n,,"
+	authMessage := s.clientFirstBare + "," + serverFirst + "," + withoutProof
+
+	proof := hmacSum(s.hash, storedKey.Sum(nil), authMessage)
+	for i := range proof {
+		proof[i] ^= clientKey[i]
+	}
+	s.serverSignature = hmacSum(s.hash, hmacSum(s.hash, saltedPassword, "Server Key"), authMessage)
+
+	return withoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof), nil
+}
+
+// verifyServerFinal checks the server signature of the server-final-message, proving that the server knows the password.
+func (s *scramClient) verifyServerFinal(serverFinal string) error {
+	attrs := parseSCRAMAttributes(serverFinal)
+	if message, ok := attrs["e"]; ok {
+		return fmt.Errorf("server error: %s", message)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(attrs["v"])
+	if err != nil || !hmac.Equal(signature, s.serverSignature) {
+		return errors.New("invalid server signature")
+	}
+	return nil
+}
+
+// parseSCRAMAttributes parses a SCRAM message of comma separated "key=value" attributes.
+func parseSCRAMAttributes(message string) map[string]string {
+	attrs := make(map[string]string)
+	for _, attr := range strings.Split(message, ",") {
+		if key, value, ok := strings.Cut(attr, "="); ok {
+			attrs[key] = value
+		}
+	}
+	return attrs
+}
+
+// scramEscape escapes "=" and "," in a username.
+func scramEscape(username string) string {
+	return strings.NewReplacer("=", "=3D", ",", "=2C").Replace(username)
+}
+
+// hmacSum returns the HMAC of message using key.
+func hmacSum(h func() hash.Hash, key []byte, message string) []byte {
+	mac := hmac.New(h, key)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+// pbkdf2Key derives a key from password and salt (RFC 8018).
+func pbkdf2Key(h func() hash.Hash, password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(h, password)
+
+	var key []byte
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(binary.BigEndian.AppendUint32(nil, block))
+		u := prf.Sum(nil)
+		t := bytes.Clone(u)
+
+		for range iterations - 1 {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for i := range t {
+				t[i] ^= u[i]
+			}
+		}
+		key = append(key, t...)
+	}
+
+	return key[:keyLen]
+}
@@ -0,0 +1,75 @@
+package checker
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSCRAMClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RFC 7677 Example", func(t *testing.T) {
+		t.Parallel()
+
+		client := newSCRAMClient(sha256.New, "user", "pencil", "rOprNGfwEbeRWgbNEkqO")
+		assert.Equal(t, "n,,n=user,r=rOprNGfwEbeRWgbNEkqO", client.clientFirst())
+
+		clientFinal, err := client.clientFinal("r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096")
+		assert.NoError(t, err)
+		assert.Equal(t, "c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=", clientFinal)
+
+		assert.NoError(t, client.verifyServerFinal("v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4="))
+		assert.EqualError(t, client.verifyServerFinal("v=AAAA"), "invalid server signature")
+	})
+
+	t.Run("RFC 5802 Example", func(t *testing.T) {
+		t.Parallel()
+
+		client := newSCRAMClient(sha1.New, "user", "pencil", "fyko+d2lbbFgONRv9qkxdawL")
+
+		clientFinal, err := client.clientFinal("r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j,s=QSXCR+Q6sek8bf92,i=4096")
+		assert.NoError(t, err)
+		assert.Equal(t, "c=biws,r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j,p=v0X8v3Bz2T0CJGbJQyF0X+HI4Ts=", clientFinal)
+		assert.NoError(t, client.verifyServerFinal("v=rmF9pqV8S7suAoZWja4dJRkFsKQ="))
+	})
+
+	t.Run("Invalid Server First", func(t *testing.T) {
+		t.Parallel()
+
+		client := newSCRAMClient(sha256.New, "user", "pencil", "nonce")
+
+		_, err := client.clientFinal("r=other,s=c2FsdA==,i=4096")
+		assert.EqualError(t, err, "invalid server nonce")
+
+		_, err = client.clientFinal("r=nonce-server,s=c2FsdA==,i=0")
+		assert.EqualError(t, err, `invalid iteration count: "0"`)
+
+		_, err = client.clientFinal("r=nonce-server,s=c2FsdA==,i=2147483647")
+		assert.EqualError(t, err, "iteration count 2147483647 exceeds the maximum of 1000000")
+
+		_, err = client.clientFinal("e=unknown-user")
+		assert.EqualError(t, err, "server error: unknown-user")
+	})
+
+	t.Run("Escaped Username", func(t *testing.T) {
+		t.Parallel()
+
+		client := newSCRAMClient(sha256.New, "a=b,c", "pencil", "nonce")
+		assert.Equal(t, "n,,n=a=3Db=2Cc,r=nonce", client.clientFirst())
+	})
+}
+
+func TestPBKDF2Key(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors from RFC 6070
+	key := pbkdf2Key(sha1.New, []byte("password"), []byte("salt"), 4096, 20)
+	assert.Equal(t, "4b007901b765489abead49d926f721d065a429c1", hex.EncodeToString(key))
+
+	key = pbkdf2Key(sha1.New, []byte("passwordPASSWORDpassword"), []byte("saltSALTsaltSALTsaltSALTsaltSALTsalt"), 4096, 25)
+	assert.Equal(t, "3d2eec4fe41c849b80c8d83662c0e44a8b291a964cf2f07038", hex.EncodeToString(key))
+}
@@ -2,29 +2,105 @@ package checker
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"net"
+	"regexp"
 	"time"
 )
 
-const defaultTCPTimeout time.Duration = 1 * time.Second
+const (
+	defaultTCPTimeout     time.Duration = 1 * time.Second
+	defaultTCPReadTimeout time.Duration = 1 * time.Second
+	maxTCPResponseSize    int           = 64 * 1024 // Bytes read at most while waiting for the expected response
+)
 
 // TCPChecker implements the Checker interface for TCP checks.
 type TCPChecker struct {
-	name    string
-	address string
-	dialer  *net.Dialer
+	name         string
+	address      string
+	resolve      map[string]string
+	allAddresses bool
+	minAddresses int
+	send         []byte          // Probe written after connecting
+	response     responseMatcher // Expected response after connecting (and sending the probe)
+	readTimeout  time.Duration   // Timeout for sending the probe and receiving the response
+	lastResults  AddressResults
+	dialer       *net.Dialer
+	dial         dialContextFunc
 }
 
-func (c *TCPChecker) Address() string { return c.address }
-func (c *TCPChecker) Name() string    { return c.name }
-func (c *TCPChecker) Type() string    { return TCP.String() }
+func (c *TCPChecker) Address() string      { return c.address }
+func (c *TCPChecker) Name() string         { return c.name }
+func (c *TCPChecker) Type() string         { return TCP.String() }
+func (c *TCPChecker) Details() []slog.Attr { return addressDetails(c.lastResults) }
 func (c *TCPChecker) Check(ctx context.Context) error {
-	conn, err := c.dialer.DialContext(ctx, "tcp", c.address)
+	c.lastResults = nil
+
+	if !c.allAddresses {
+		return c.checkAddress(ctx, c.address)
+	}
+
+	host, port, err := net.SplitHostPort(c.address)
+	if err != nil {
+		return err
+	}
+
+	ips, err := lookupTargetIPs(ctx, host, port, c.resolve)
+	if err != nil {
+		return err
+	}
+
+	c.lastResults, err = checkAllAddresses(ctx, ips, c.minAddresses, true, func(ctx context.Context, ip net.IP) error {
+		return c.checkAddress(ctx, net.JoinHostPort(ip.String(), port))
+	})
+	return err
+}
+
+// checkAddress connects to a single address and, if configured, exchanges the probe and response.
+func (c *TCPChecker) checkAddress(ctx context.Context, address string) error {
+	conn, err := c.dial(ctx, "tcp", address)
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
-	return nil
+
+	if len(c.send) == 0 && !c.response.configured() {
+		return nil
+	}
+	return c.exchange(conn)
+}
+
+// exchange writes the probe and reads until the response matches, the connection is closed or the read timeout expires.
+func (c *TCPChecker) exchange(conn net.Conn) error {
+	if err := conn.SetDeadline(time.Now().Add(c.readTimeout)); err != nil {
+		return fmt.Errorf("failed to set deadline: %w", err)
+	}
+
+	if len(c.send) > 0 {
+		if _, err := conn.Write(c.send); err != nil {
+			return fmt.Errorf("failed to send probe: %w", err)
+		}
+	}
+
+	if !c.response.configured() {
+		return nil
+	}
+
+	var received []byte
+	buf := make([]byte, 4096)
+	for len(received) < maxTCPResponseSize {
+		n, err := conn.Read(buf)
+		received = append(received, buf[:n]...)
+		if c.response.matches(received) {
+			return nil
+		}
+		if err != nil {
+			return c.response.mismatch(received, err)
+		}
+	}
+
+	return c.response.mismatch(received, nil)
 }
 
 // newTCPChecker creates a new TCPChecker with functional options.
@@ -35,12 +111,15 @@ func newTCPChecker(name, address string, opts ...Option) (*TCPChecker, error) {
 		dialer: &net.Dialer{
 			Timeout: defaultTCPTimeout,
 		},
+		readTimeout: defaultTCPReadTimeout,
 	}
 
 	for _, opt := range opts {
 		opt.apply(checker)
 	}
 
+	checker.dial = overrideDialContext(checker.dialer, checker.resolve)
+
 	return checker, nil
 }
 
@@ -52,3 +131,59 @@ func WithTCPTimeout(timeout time.Duration) Option {
 		}
 	})
 }
+
+// WithTCPResolve sets "host:port" to IP overrides for the TCPChecker, similar to curl's --resolve.
+func WithTCPResolve(overrides map[string]string) Option {
+	return OptionFunc(func(c Checker) {
+		if tcpChecker, ok := c.(*TCPChecker); ok {
+			tcpChecker.resolve = overrides
+		}
+	})
+}
+
+// WithTCPAllAddresses makes the TCPChecker connect to every resolved IP address of the host. The check passes
+// if at least minAddresses addresses are reachable; zero requires all of them.
+func WithTCPAllAddresses(minAddresses int) Option {
+	return OptionFunc(func(c Checker) {
+		if tcpChecker, ok := c.(*TCPChecker); ok {
+			tcpChecker.allAddresses = true
+			tcpChecker.minAddresses = minAddresses
+		}
+	})
+}
+
+// WithTCPSend sets a probe the TCPChecker writes after connecting.
+func WithTCPSend(data []byte) Option {
+	return OptionFunc(func(c Checker) {
+		if tcpChecker, ok := c.(*TCPChecker); ok {
+			tcpChecker.send = data
+		}
+	})
+}
+
+// WithTCPExpect makes the TCPChecker wait for a response containing data after connecting.
+func WithTCPExpect(data []byte) Option {
+	return OptionFunc(func(c Checker) {
+		if tcpChecker, ok := c.(*TCPChecker); ok {
+			tcpChecker.response.expect = data
+		}
+	})
+}
+
+// WithTCPExpectPattern makes the TCPChecker wait for a response matching re after connecting.
+func WithTCPExpectPattern(re *regexp.Regexp) Option {
+	return OptionFunc(func(c Checker) {
+		if tcpChecker, ok := c.(*TCPChecker); ok {
+			tcpChecker.response.expectRegex = re
+		}
+	})
+}
+
+// WithTCPReadTimeout sets the timeout for sending the probe and receiving the expected response.
+func WithTCPReadTimeout(timeout time.Duration) Option {
+	return OptionFunc(func(c Checker) {
+		if tcpChecker, ok := c.(*TCPChecker); ok {
+			tcpChecker.readTimeout = timeout
+		}
+	})
+}
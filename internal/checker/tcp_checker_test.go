@@ -1,8 +1,12 @@
 package checker
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"net"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -86,3 +90,191 @@ func TestTCPChecker_Timeout(t *testing.T) {
 	assert.Error(t, err)
 	assert.EqualError(t, err, "dial tcp 127.0.0.1:7082: i/o timeout")
 }
+
+func TestTCPChecker_ResolveOverride(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:7083")
+	if err != nil {
+		t.Fatalf("failed to start TCP server: %q", err)
+	}
+	defer ln.Close()
+
+	checker, err := newTCPChecker("example", "db.portpatrol.test:7083",
+		WithTCPTimeout(1*time.Second),
+		WithTCPResolve(map[string]string{"db.portpatrol.test:7083": "127.0.0.1"}),
+	)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	err = checker.Check(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "db.portpatrol.test:7083", checker.Address())
+}
+
+func TestTCPChecker_AllAddresses(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:7084")
+	if err != nil {
+		t.Fatalf("failed to start TCP server: %q", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	t.Run("Every address reachable", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newTCPChecker("example", "127.0.0.1:7084", WithTCPAllAddresses(0))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "addresses=127.0.0.1=ok", checker.Details()[0].String())
+	})
+
+	t.Run("Results cleared when lookup fails", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newTCPChecker("example", "127.0.0.1:7084", WithTCPAllAddresses(0))
+		assert.NoError(t, err)
+		assert.NoError(t, checker.Check(context.Background()))
+		assert.NotEmpty(t, checker.Details())
+
+		checker.address = "db.portpatrol.test:7084" // Reserved TLD, never resolves
+		assert.Error(t, checker.Check(context.Background()))
+		assert.Empty(t, checker.Details())
+	})
+
+	t.Run("Address not reachable", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newTCPChecker("example", "127.0.0.1:7091", WithTCPAllAddresses(0))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "0 of 1 addresses reachable, 1 required: 127.0.0.1=dial tcp 127.0.0.1:7091: connect: connection refused")
+	})
+}
+
+func TestTCPChecker_SendExpect(t *testing.T) {
+	t.Parallel()
+
+	// serve accepts connections and answers each with the banner and the response to the first line received.
+	serve := func(t *testing.T, banner string, respond func(line string) string) string {
+		t.Helper()
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to start TCP server: %q", err)
+		}
+		t.Cleanup(func() { ln.Close() })
+
+		go func() {
+			for {
+				conn, err := ln.Accept()
+				if err != nil {
+					return
+				}
+				go func() {
+					defer conn.Close()
+					_, _ = conn.Write([]byte(banner))
+					if respond == nil {
+						time.Sleep(500 * time.Millisecond)
+						return
+					}
+					line, _ := bufio.NewReader(conn).ReadString('\n')
+					_, _ = conn.Write([]byte(respond(line)))
+				}()
+			}
+		}()
+
+		return ln.Addr().String()
+	}
+
+	t.Run("Banner Matches", func(t *testing.T) {
+		t.Parallel()
+
+		address := serve(t, "220 mail.example.com ESMTP\r\n", nil)
+		checker, err := newTCPChecker("smtp", address, WithTCPExpect([]byte("220 ")))
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Banner Pattern Matches", func(t *testing.T) {
+		t.Parallel()
+
+		address := serve(t, "220 mail.example.com ESMTP\r\n", nil)
+		checker, err := newTCPChecker("smtp", address, WithTCPExpectPattern(regexp.MustCompile(`^220 \S+ ESMTP`)))
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Send And Expect", func(t *testing.T) {
+		t.Parallel()
+
+		address := serve(t, "", func(line string) string {
+			if line == "stats\r\n" {
+				return "STAT pid 1\r\nEND\r\n"
+			}
+			return "ERROR\r\n"
+		})
+		checker, err := newTCPChecker("memcached", address,
+			WithTCPSend([]byte("stats\r\n")),
+			WithTCPExpect([]byte("END\r\n")),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Mismatch Reports Received Bytes", func(t *testing.T) {
+		t.Parallel()
+
+		address := serve(t, "", func(line string) string { return "-ERR unknown command\r\n" })
+		checker, err := newTCPChecker("redis", address,
+			WithTCPSend([]byte("PING\r\n")),
+			WithTCPExpect([]byte("+PONG")),
+		)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, `unexpected response, expected "+PONG": received 22 bytes "-ERR unknown command\r\n": EOF`)
+	})
+
+	t.Run("Read Timeout", func(t *testing.T) {
+		t.Parallel()
+
+		address := serve(t, "starting", nil)
+		checker, err := newTCPChecker("slow", address,
+			WithTCPExpect([]byte("ready")),
+			WithTCPReadTimeout(100*time.Millisecond),
+		)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `unexpected response, expected "ready": received 8 bytes "starting": `)
+		assert.Contains(t, err.Error(), "i/o timeout")
+	})
+
+	t.Run("Send Only", func(t *testing.T) {
+		t.Parallel()
+
+		address := serve(t, "", nil)
+		checker, err := newTCPChecker("send", address, WithTCPSend([]byte("hello\n")))
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+}
+
+func TestQuoteBytes(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `"ok\r\n"`, quoteBytes([]byte("ok\r\n")))
+
+	long := quoteBytes(bytes.Repeat([]byte("a"), maxReportedBytes+10))
+	assert.Equal(t, `"`+strings.Repeat("a", maxReportedBytes)+`"...`, long)
+}
@@ -0,0 +1,163 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+)
+
+const defaultTLSTimeout time.Duration = 2 * time.Second
+
+// TLSChecker implements the Checker interface for TLS handshake and certificate checks.
+type TLSChecker struct {
+	name                string
+	address             string
+	serverName          string         // SNI and verified hostname, defaults to the host of the address
+	rootCAs             *x509.CertPool // Trusted CAs, nil uses the system pool
+	minValidity         time.Duration  // Minimum remaining validity of the leaf certificate
+	expectedSAN         string         // DNS name or IP the leaf certificate must be valid for
+	expectedFingerprint []byte         // SHA-256 fingerprint the leaf certificate must have
+	lastCertificate     *x509.Certificate
+	dialer              *net.Dialer
+}
+
+func (c *TLSChecker) Address() string { return c.address }
+func (c *TLSChecker) Name() string    { return c.name }
+func (c *TLSChecker) Type() string    { return TLS.String() }
+
+// Details returns the subject, issuer and expiry of the certificate presented in the last check.
+func (c *TLSChecker) Details() []slog.Attr {
+	if c.lastCertificate == nil {
+		return nil
+	}
+	return []slog.Attr{
+		slog.String("subject", c.lastCertificate.Subject.String()),
+		slog.String("issuer", c.lastCertificate.Issuer.String()),
+		slog.String("not_after", c.lastCertificate.NotAfter.UTC().Format(time.RFC3339)),
+	}
+}
+
+func (c *TLSChecker) Check(ctx context.Context) error {
+	c.lastCertificate = nil
+
+	dialer := &tls.Dialer{
+		NetDialer: c.dialer,
+		Config: &tls.Config{
+			ServerName: c.serverName,
+			RootCAs:    c.rootCAs,
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	defer conn.Close()
+
+	certificates := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		return errors.New("no certificate presented")
+	}
+	leaf := certificates[0]
+	c.lastCertificate = leaf
+
+	if c.minValidity > 0 {
+		if remaining := time.Until(leaf.NotAfter); remaining < c.minValidity {
+			return fmt.Errorf("certificate expires at %s (in %s), required validity is %s",
+				leaf.NotAfter.UTC().Format(time.RFC3339), remaining.Round(time.Second), c.minValidity)
+		}
+	}
+
+	if c.expectedSAN != "" {
+		if err := leaf.VerifyHostname(c.expectedSAN); err != nil {
+			return fmt.Errorf("certificate does not match expected SAN %q: %w", c.expectedSAN, err)
+		}
+	}
+
+	if len(c.expectedFingerprint) > 0 {
+		if fingerprint := sha256.Sum256(leaf.Raw); !bytes.Equal(fingerprint[:], c.expectedFingerprint) {
+			return fmt.Errorf("certificate fingerprint mismatch: got %X, expected %X", fingerprint, c.expectedFingerprint)
+		}
+	}
+
+	return nil
+}
+
+// newTLSChecker creates a new TLSChecker with functional options.
+func newTLSChecker(name, address string, opts ...Option) (*TLSChecker, error) {
+	checker := &TLSChecker{
+		name:    name,
+		address: address,
+		dialer: &net.Dialer{
+			Timeout: defaultTLSTimeout,
+		},
+	}
+
+	for _, opt := range opts {
+		opt.apply(checker)
+	}
+
+	return checker, nil
+}
+
+// WithTLSTimeout sets the timeout for connecting and completing the handshake.
+func WithTLSTimeout(timeout time.Duration) Option {
+	return OptionFunc(func(c Checker) {
+		if tlsChecker, ok := c.(*TLSChecker); ok {
+			tlsChecker.dialer.Timeout = timeout
+		}
+	})
+}
+
+// WithTLSServerName sets the server name sent via SNI and verified against the certificate.
+func WithTLSServerName(serverName string) Option {
+	return OptionFunc(func(c Checker) {
+		if tlsChecker, ok := c.(*TLSChecker); ok {
+			tlsChecker.serverName = serverName
+		}
+	})
+}
+
+// WithTLSRootCAs sets the CAs trusted to verify the certificate chain instead of the system pool.
+func WithTLSRootCAs(pool *x509.CertPool) Option {
+	return OptionFunc(func(c Checker) {
+		if tlsChecker, ok := c.(*TLSChecker); ok {
+			tlsChecker.rootCAs = pool
+		}
+	})
+}
+
+// WithTLSMinValidity requires the leaf certificate to be valid for at least the given duration.
+func WithTLSMinValidity(minValidity time.Duration) Option {
+	return OptionFunc(func(c Checker) {
+		if tlsChecker, ok := c.(*TLSChecker); ok {
+			tlsChecker.minValidity = minValidity
+		}
+	})
+}
+
+// WithTLSExpectedSAN requires the leaf certificate to be valid for the given DNS name or IP address.
+func WithTLSExpectedSAN(san string) Option {
+	return OptionFunc(func(c Checker) {
+		if tlsChecker, ok := c.(*TLSChecker); ok {
+			tlsChecker.expectedSAN = san
+		}
+	})
+}
+
+// WithTLSExpectedFingerprint requires the SHA-256 fingerprint of the leaf certificate to match.
+func WithTLSExpectedFingerprint(fingerprint []byte) Option {
+	return OptionFunc(func(c Checker) {
+		if tlsChecker, ok := c.(*TLSChecker); ok {
+			tlsChecker.expectedFingerprint = fingerprint
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package checker
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLSChecker(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // Rejected handshakes are expected
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	address := strings.TrimPrefix(server.URL, "https://")
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	t.Run("Valid Certificate", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newTLSChecker("tls", address, WithTLSRootCAs(pool))
+		assert.NoError(t, err)
+		assert.Equal(t, "TLS", checker.Type())
+
+		assert.NoError(t, checker.Check(context.Background()))
+
+		details := checker.Details()
+		assert.Len(t, details, 3)
+		assert.Equal(t, "subject", details[0].Key)
+		assert.Contains(t, details[0].Value.String(), "O=Acme Co")
+		assert.Equal(t, "issuer", details[1].Key)
+		assert.Equal(t, "not_after", details[2].Key)
+		assert.Equal(t, server.Certificate().NotAfter.UTC().Format(time.RFC3339), details[2].Value.String())
+	})
+
+	t.Run("Unknown Authority", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newTLSChecker("tls", address)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "TLS handshake failed: ")
+		assert.Contains(t, err.Error(), "certificate signed by unknown authority")
+		assert.Nil(t, checker.Details())
+	})
+
+	t.Run("Server Name", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newTLSChecker("tls", address, WithTLSRootCAs(pool), WithTLSServerName("example.com"))
+		assert.NoError(t, err)
+		assert.NoError(t, checker.Check(context.Background()))
+
+		checker, err = newTLSChecker("tls", address, WithTLSRootCAs(pool), WithTLSServerName("other.example.org"))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not other.example.org")
+	})
+
+	t.Run("Minimum Validity", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newTLSChecker("tls", address, WithTLSRootCAs(pool), WithTLSMinValidity(24*time.Hour))
+		assert.NoError(t, err)
+		assert.NoError(t, checker.Check(context.Background()))
+
+		checker, err = newTLSChecker("tls", address, WithTLSRootCAs(pool), WithTLSMinValidity(200*365*24*time.Hour))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "certificate expires at "+server.Certificate().NotAfter.UTC().Format(time.RFC3339))
+		assert.Contains(t, err.Error(), "required validity is 1752000h0m0s")
+	})
+
+	t.Run("Expected SAN", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newTLSChecker("tls", address, WithTLSRootCAs(pool), WithTLSExpectedSAN("example.com"))
+		assert.NoError(t, err)
+		assert.NoError(t, checker.Check(context.Background()))
+
+		checker, err = newTLSChecker("tls", address, WithTLSRootCAs(pool), WithTLSExpectedSAN("api.example.org"))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `certificate does not match expected SAN "api.example.org": `)
+	})
+
+	t.Run("Expected Fingerprint", func(t *testing.T) {
+		t.Parallel()
+
+		fingerprint := sha256.Sum256(server.Certificate().Raw)
+		checker, err := newTLSChecker("tls", address, WithTLSRootCAs(pool), WithTLSExpectedFingerprint(fingerprint[:]))
+		assert.NoError(t, err)
+		assert.NoError(t, checker.Check(context.Background()))
+
+		checker, err = newTLSChecker("tls", address, WithTLSRootCAs(pool), WithTLSExpectedFingerprint(make([]byte, sha256.Size)))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "certificate fingerprint mismatch: got ")
+	})
+
+	t.Run("Connection Refused", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newTLSChecker("tls", "127.0.0.1:7095", WithTLSTimeout(time.Second))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "TLS handshake failed: dial tcp 127.0.0.1:7095: connect: connection refused")
+	})
+}
@@ -0,0 +1,132 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultUDPTimeout  time.Duration = 2 * time.Second
+	maxUDPDatagramSize int           = 65535
+)
+
+// UDPChecker implements the Checker interface for UDP request/response checks. Since UDP is connectionless,
+// the target only counts as available once it answers the probe datagram.
+type UDPChecker struct {
+	name     string
+	address  string
+	send     []byte          // Probe datagram
+	response responseMatcher // Expected reply, any reply is accepted if not configured
+	timeout  time.Duration   // Timeout for sending the probe and receiving the reply
+	dialer   *net.Dialer
+}
+
+func (c *UDPChecker) Address() string { return c.address }
+func (c *UDPChecker) Name() string    { return c.name }
+func (c *UDPChecker) Type() string    { return UDP.String() }
+
+func (c *UDPChecker) Check(ctx context.Context) error {
+	conn, err := c.dialer.DialContext(ctx, "udp", c.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("failed to set deadline: %w", err)
+	}
+
+	if _, err := conn.Write(c.send); err != nil {
+		return c.wrapError("failed to send probe", err)
+	}
+
+	// Every datagram is a complete reply, so each one is matched on its own. Unrelated datagrams
+	// are skipped until a matching reply arrives or the timeout expires.
+	var last []byte
+	received := false
+	buf := make([]byte, maxUDPDatagramSize)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			if received {
+				return c.response.mismatch(last, err)
+			}
+			return c.wrapError("failed to read reply", err)
+		}
+
+		if !c.response.configured() || c.response.matches(buf[:n]) {
+			return nil
+		}
+		last = append(last[:0], buf[:n]...)
+		received = true
+	}
+}
+
+// wrapError reports an ICMP port unreachable, which the kernel surfaces as a refused connection, as definitive failure.
+func (c *UDPChecker) wrapError(action string, err error) error {
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("port unreachable: %w", err)
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+// newUDPChecker creates a new UDPChecker with functional options.
+func newUDPChecker(name, address string, opts ...Option) (*UDPChecker, error) {
+	checker := &UDPChecker{
+		name:    name,
+		address: address,
+		timeout: defaultUDPTimeout,
+		dialer:  &net.Dialer{},
+	}
+
+	for _, opt := range opts {
+		opt.apply(checker)
+	}
+
+	return checker, nil
+}
+
+// WithUDPTimeout sets the timeout for sending the probe and receiving the reply.
+func WithUDPTimeout(timeout time.Duration) Option {
+	return OptionFunc(func(c Checker) {
+		if udpChecker, ok := c.(*UDPChecker); ok {
+			udpChecker.timeout = timeout
+		}
+	})
+}
+
+// WithUDPSend sets the probe datagram the UDPChecker sends. Without it, an empty datagram is sent.
+func WithUDPSend(data []byte) Option {
+	return OptionFunc(func(c Checker) {
+		if udpChecker, ok := c.(*UDPChecker); ok {
+			udpChecker.send = data
+		}
+	})
+}
+
+// WithUDPExpect makes the UDPChecker wait for a reply containing data.
+func WithUDPExpect(data []byte) Option {
+	return OptionFunc(func(c Checker) {
+		if udpChecker, ok := c.(*UDPChecker); ok {
+			udpChecker.response.expect = data
+		}
+	})
+}
+
+// WithUDPExpectPattern makes the UDPChecker wait for a reply matching re.
+func WithUDPExpectPattern(re *regexp.Regexp) Option {
+	return OptionFunc(func(c Checker) {
+		if udpChecker, ok := c.(*UDPChecker); ok {
+			udpChecker.response.expectRegex = re
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package checker
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// serveUDP starts a local UDP listener that answers every datagram with the replies returned by handle.
+func serveUDP(t *testing.T, handle func(request string) []string) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start UDP server: %q", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go func() {
+		buf := make([]byte, maxUDPDatagramSize)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			for _, reply := range handle(string(buf[:n])) {
+				_, _ = conn.WriteTo([]byte(reply), addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+// closedUDPAddress returns the address of a UDP port nobody listens on.
+func closedUDPAddress(t *testing.T) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to reserve UDP port: %q", err)
+	}
+	address := conn.LocalAddr().String()
+	_ = conn.Close()
+
+	return address
+}
+
+func TestNewUDPChecker(t *testing.T) {
+	t.Parallel()
+
+	checker, err := newUDPChecker("example", "127.0.0.1:514", WithUDPTimeout(1*time.Second))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "example", checker.Name())
+	assert.Equal(t, "127.0.0.1:514", checker.Address())
+	assert.Equal(t, UDP.String(), checker.Type())
+	assert.Equal(t, 1*time.Second, checker.timeout)
+}
+
+func TestUDPChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Any Reply", func(t *testing.T) {
+		t.Parallel()
+
+		address := serveUDP(t, func(request string) []string { return []string{"pong"} })
+		checker, err := newUDPChecker("echo", address, WithUDPSend([]byte("ping")))
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Send And Expect", func(t *testing.T) {
+		t.Parallel()
+
+		address := serveUDP(t, func(request string) []string {
+			if request == "stats" {
+				return []string{"uptime: 42\n"}
+			}
+			return []string{"error\n"}
+		})
+		checker, err := newUDPChecker("statsd", address,
+			WithUDPSend([]byte("stats")),
+			WithUDPExpect([]byte("uptime")),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Skips Unrelated Replies", func(t *testing.T) {
+		t.Parallel()
+
+		address := serveUDP(t, func(request string) []string { return []string{"noise", "READY 1"} })
+		checker, err := newUDPChecker("game", address,
+			WithUDPSend([]byte("status")),
+			WithUDPExpectPattern(regexp.MustCompile(`^READY \d+$`)),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Mismatch Reports Received Bytes", func(t *testing.T) {
+		t.Parallel()
+
+		address := serveUDP(t, func(request string) []string { return []string{"denied\n"} })
+		checker, err := newUDPChecker("radius", address,
+			WithUDPSend([]byte("status")),
+			WithUDPExpect([]byte("accepted")),
+			WithUDPTimeout(100*time.Millisecond),
+		)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `unexpected response, expected "accepted": received 7 bytes "denied\n": `)
+		assert.Contains(t, err.Error(), "i/o timeout")
+	})
+
+	t.Run("No Reply", func(t *testing.T) {
+		t.Parallel()
+
+		address := serveUDP(t, func(request string) []string { return nil })
+		checker, err := newUDPChecker("silent", address, WithUDPTimeout(100*time.Millisecond))
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read reply: ")
+		assert.Contains(t, err.Error(), "i/o timeout")
+	})
+
+	t.Run("Port Unreachable", func(t *testing.T) {
+		t.Parallel()
+
+		address := closedUDPAddress(t)
+		checker, err := newUDPChecker("closed", address, WithUDPTimeout(1*time.Second))
+		assert.NoError(t, err)
+
+		start := time.Now()
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "port unreachable: ")
+		assert.Contains(t, err.Error(), "connection refused")
+		assert.Less(t, time.Since(start), 1*time.Second)
+	})
+
+	t.Run("Context Deadline", func(t *testing.T) {
+		t.Parallel()
+
+		address := serveUDP(t, func(request string) []string { return nil })
+		checker, err := newUDPChecker("silent", address, WithUDPTimeout(5*time.Second))
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err = checker.Check(ctx)
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+}
@@ -0,0 +1,74 @@
+package checker
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+)
+
+const defaultUnixTimeout time.Duration = 1 * time.Second
+
+// UnixChecker implements the Checker interface for unix domain socket checks.
+type UnixChecker struct {
+	name     string
+	address  string
+	path     string // Socket path without the optional "unix://" scheme
+	datagram bool   // Connect to a datagram instead of a stream socket
+	dialer   *net.Dialer
+}
+
+func (c *UnixChecker) Address() string { return c.address }
+func (c *UnixChecker) Name() string    { return c.name }
+func (c *UnixChecker) Type() string    { return Unix.String() }
+
+func (c *UnixChecker) Check(ctx context.Context) error {
+	network := "unix"
+	if c.datagram {
+		network = "unixgram"
+	}
+
+	conn, err := c.dialer.DialContext(ctx, network, c.path)
+	if err != nil {
+		return err
+	}
+	_ = conn.Close()
+
+	return nil
+}
+
+// newUnixChecker creates a new UnixChecker with functional options.
+func newUnixChecker(name, address string, opts ...Option) (*UnixChecker, error) {
+	checker := &UnixChecker{
+		name:    name,
+		address: address,
+		path:    strings.TrimPrefix(address, unixSocketScheme),
+		dialer: &net.Dialer{
+			Timeout: defaultUnixTimeout,
+		},
+	}
+
+	for _, opt := range opts {
+		opt.apply(checker)
+	}
+
+	return checker, nil
+}
+
+// WithUnixTimeout sets the timeout for connecting to the socket.
+func WithUnixTimeout(timeout time.Duration) Option {
+	return OptionFunc(func(c Checker) {
+		if unixChecker, ok := c.(*UnixChecker); ok {
+			unixChecker.dialer.Timeout = timeout
+		}
+	})
+}
+
+// WithUnixDatagram makes the UnixChecker connect to a datagram socket instead of a stream socket.
+func WithUnixDatagram(datagram bool) Option {
+	return OptionFunc(func(c Checker) {
+		if unixChecker, ok := c.(*UnixChecker); ok {
+			unixChecker.datagram = datagram
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package checker
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUnixChecker(t *testing.T) {
+	t.Parallel()
+
+	checker, err := newUnixChecker("example", "unix:///var/run/app.sock", WithUnixTimeout(2*time.Second))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "example", checker.Name())
+	assert.Equal(t, "unix:///var/run/app.sock", checker.Address())
+	assert.Equal(t, Unix.String(), checker.Type())
+	assert.Equal(t, "/var/run/app.sock", checker.path)
+	assert.Equal(t, 2*time.Second, checker.dialer.Timeout)
+}
+
+func TestUnixChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Stream Socket", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "app.sock")
+		ln, err := net.Listen("unix", path)
+		assert.NoError(t, err)
+		defer ln.Close()
+
+		checker, err := newUnixChecker("stream", path)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Datagram Socket", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "app.sock")
+		conn, err := net.ListenPacket("unixgram", path)
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		checker, err := newUnixChecker("datagram", "unix://"+path, WithUnixDatagram(true))
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Wrong Socket Type", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "app.sock")
+		conn, err := net.ListenPacket("unixgram", path)
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		checker, err := newUnixChecker("stream", path)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "dial unix "+path)
+	})
+
+	t.Run("Missing Socket", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "missing.sock")
+		checker, err := newUnixChecker("missing", path)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.EqualError(t, err, "dial unix "+path+": connect: no such file or directory")
+	})
+}
@@ -0,0 +1,440 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	defaultWebSocketTimeout time.Duration = 2 * time.Second
+	maxWebSocketMessageSize int           = 64 * 1024 // Largest message read while waiting for the expected reply
+	webSocketGUID           string        = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+// WebSocket frame opcodes (RFC 6455, section 5.2).
+const (
+	wsOpContinuation byte = 0x0
+	wsOpText         byte = 0x1
+	wsOpBinary       byte = 0x2
+	wsOpClose        byte = 0x8
+	wsOpPing         byte = 0x9
+	wsOpPong         byte = 0xA
+)
+
+// wsCloseNormal is the status code of a normal closure.
+const wsCloseNormal uint16 = 1000
+
+// WebSocketChecker implements the Checker interface for WebSocket checks. It performs the upgrade handshake,
+// optionally exchanges a text message and closes the connection with a close handshake.
+type WebSocketChecker struct {
+	name          string
+	address       string
+	headers       map[string]string
+	skipTLSVerify bool
+	timeout       time.Duration   // Timeout for the whole check including the close handshake
+	send          []byte          // Text message sent after the upgrade
+	response      responseMatcher // Expected reply, not awaited if not configured
+	dialer        *net.Dialer
+}
+
+func (c *WebSocketChecker) Address() string { return c.address }
+func (c *WebSocketChecker) Name() string    { return c.name }
+func (c *WebSocketChecker) Type() string    { return WebSocket.String() }
+
+func (c *WebSocketChecker) Check(ctx context.Context) error {
+	u, err := url.Parse(c.address)
+	if err != nil {
+		return fmt.Errorf("failed to parse address: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := c.dial(ctx, u)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("failed to set deadline: %w", err)
+		}
+	}
+
+	br := bufio.NewReader(conn)
+	if err := c.handshake(conn, br, u); err != nil {
+		return err
+	}
+
+	if len(c.send) > 0 {
+		if err := writeWebSocketFrame(conn, wsOpText, c.send); err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+	}
+
+	if c.response.configured() {
+		if err := c.awaitReply(conn, br); err != nil {
+			return err
+		}
+	}
+
+	if err := closeWebSocket(conn, br); err != nil {
+		return fmt.Errorf("failed to close connection cleanly: %w", err)
+	}
+
+	return nil
+}
+
+// dial connects to the host of u, completing the TLS handshake for wss.
+func (c *WebSocketChecker) dial(ctx context.Context, u *url.URL) (net.Conn, error) {
+	var port string
+	switch u.Scheme {
+	case "ws":
+		port = "80"
+	case "wss":
+		port = "443"
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q: must be ws or wss", u.Scheme)
+	}
+	if u.Port() != "" {
+		port = u.Port()
+	}
+
+	conn, err := c.dialer.DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "ws" {
+		return conn, nil
+	}
+
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName:         u.Hostname(),
+		InsecureSkipVerify: c.skipTLSVerify,
+	})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	return tlsConn, nil
+}
+
+// handshake sends the upgrade request and validates the server's response (RFC 6455, section 4).
+func (c *WebSocketChecker) handshake(conn net.Conn, br *bufio.Reader, u *url.URL) error {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: u.RawQuery},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Host:       u.Host,
+	}
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	if err := req.Write(conn); err != nil {
+		return fmt.Errorf("failed to send upgrade request: %w", err)
+	}
+
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return fmt.Errorf("failed to read upgrade response: %w", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		_ = resp.Body.Close()
+		return fmt.Errorf("upgrade rejected: got status code %d, expected 101", resp.StatusCode)
+	}
+
+	if upgrade := resp.Header.Get("Upgrade"); !strings.EqualFold(upgrade, "websocket") {
+		return fmt.Errorf("invalid Upgrade header %q", upgrade)
+	}
+	if !headerHasToken(resp.Header, "Connection", "upgrade") {
+		return fmt.Errorf("invalid Connection header %q", resp.Header.Get("Connection"))
+	}
+	if accept := resp.Header.Get("Sec-WebSocket-Accept"); accept != webSocketAccept(key) {
+		return fmt.Errorf("invalid Sec-WebSocket-Accept header %q", accept)
+	}
+
+	return nil
+}
+
+// awaitReply reads messages until one matches the expected reply. Other messages, e.g. a greeting, are skipped.
+func (c *WebSocketChecker) awaitReply(conn net.Conn, br *bufio.Reader) error {
+	var last []byte
+	for {
+		message, err := readWebSocketMessage(conn, br)
+		if err != nil {
+			if last != nil {
+				return c.response.mismatch(last, err)
+			}
+			return fmt.Errorf("failed to read reply: %w", err)
+		}
+		if c.response.matches(message) {
+			return nil
+		}
+		last = message
+	}
+}
+
+// headerHasToken reports whether the comma-separated values of header name contain token, ignoring case.
+func headerHasToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, field := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(field), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// webSocketAccept returns the Sec-WebSocket-Accept value the server must answer key with.
+func webSocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + webSocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// closeWebSocket sends a close frame and waits for the server to answer it.
+func closeWebSocket(conn net.Conn, br *bufio.Reader) error {
+	payload := binary.BigEndian.AppendUint16(nil, wsCloseNormal)
+	if err := writeWebSocketFrame(conn, wsOpClose, payload); err != nil {
+		return err
+	}
+
+	for {
+		opcode, _, _, err := readWebSocketFrame(br)
+		if err != nil {
+			return err
+		}
+		if opcode == wsOpClose {
+			return nil
+		}
+	}
+}
+
+// readWebSocketMessage reads the next data message, answering pings and reassembling fragments.
+func readWebSocketMessage(conn net.Conn, br *bufio.Reader) ([]byte, error) {
+	var message []byte
+	for {
+		opcode, fin, payload, err := readWebSocketFrame(br)
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case wsOpPing:
+			if err := writeWebSocketFrame(conn, wsOpPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			return nil, fmt.Errorf("connection closed by server%s", closeReason(payload))
+		case wsOpText, wsOpBinary, wsOpContinuation:
+		default:
+			return nil, fmt.Errorf("unexpected opcode %#x", opcode)
+		}
+
+		if len(message)+len(payload) > maxWebSocketMessageSize {
+			return nil, fmt.Errorf("message exceeds %d bytes", maxWebSocketMessageSize)
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// closeReason formats the status code and reason of a close frame payload.
+func closeReason(payload []byte) string {
+	if len(payload) < 2 {
+		return ""
+	}
+	code := binary.BigEndian.Uint16(payload)
+	if reason := payload[2:]; len(reason) > 0 {
+		return fmt.Sprintf(" with code %d: %s", code, reason)
+	}
+	return fmt.Sprintf(" with code %d", code)
+}
+
+// readWebSocketFrame reads a single frame and returns its opcode, FIN bit and unmasked payload.
+func readWebSocketFrame(r io.Reader) (opcode byte, fin bool, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, false, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, false, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, false, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > uint64(maxWebSocketMessageSize) {
+		return 0, false, nil, fmt.Errorf("frame exceeds %d bytes", maxWebSocketMessageSize)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return 0, false, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, false, nil, err
+	}
+	if masked {
+		maskBytes(payload, mask)
+	}
+
+	return opcode, fin, payload, nil
+}
+
+// writeWebSocketFrame writes a single masked frame, as required for frames sent by a client.
+func writeWebSocketFrame(w io.Writer, opcode byte, payload []byte) error {
+	frame := []byte{0x80 | opcode}
+
+	switch length := len(payload); {
+	case length <= 125:
+		frame = append(frame, 0x80|byte(length))
+	case length <= 0xFFFF:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(length))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(length))
+	}
+
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+	frame = append(frame, mask[:]...)
+
+	masked := append([]byte(nil), payload...)
+	maskBytes(masked, mask)
+	frame = append(frame, masked...)
+
+	_, err := w.Write(frame)
+	return err
+}
+
+// maskBytes applies the WebSocket masking algorithm to data in place. Applying it twice restores the data.
+func maskBytes(data []byte, mask [4]byte) {
+	for i := range data {
+		data[i] ^= mask[i%4]
+	}
+}
+
+// newWebSocketChecker creates a new WebSocketChecker with functional options.
+func newWebSocketChecker(name, address string, opts ...Option) (*WebSocketChecker, error) {
+	checker := &WebSocketChecker{
+		name:    name,
+		address: address,
+		headers: make(map[string]string),
+		timeout: defaultWebSocketTimeout,
+		dialer:  &net.Dialer{},
+	}
+
+	for _, opt := range opts {
+		opt.apply(checker)
+	}
+
+	return checker, nil
+}
+
+// WithWebSocketHeaders sets the headers sent with the upgrade request.
+func WithWebSocketHeaders(headers map[string]string) Option {
+	return OptionFunc(func(c Checker) {
+		if wsChecker, ok := c.(*WebSocketChecker); ok {
+			wsChecker.headers = headers
+		}
+	})
+}
+
+// WithWebSocketSkipTLSVerify disables the verification of the server certificate for wss.
+func WithWebSocketSkipTLSVerify(skip bool) Option {
+	return OptionFunc(func(c Checker) {
+		if wsChecker, ok := c.(*WebSocketChecker); ok {
+			wsChecker.skipTLSVerify = skip
+		}
+	})
+}
+
+// WithWebSocketTimeout sets the timeout for the whole check including the close handshake.
+func WithWebSocketTimeout(timeout time.Duration) Option {
+	return OptionFunc(func(c Checker) {
+		if wsChecker, ok := c.(*WebSocketChecker); ok {
+			wsChecker.timeout = timeout
+		}
+	})
+}
+
+// WithWebSocketSend sets a text message sent after the upgrade.
+func WithWebSocketSend(message []byte) Option {
+	return OptionFunc(func(c Checker) {
+		if wsChecker, ok := c.(*WebSocketChecker); ok {
+			wsChecker.send = message
+		}
+	})
+}
+
+// WithWebSocketExpect makes the WebSocketChecker wait for a message containing data.
+func WithWebSocketExpect(data []byte) Option {
+	return OptionFunc(func(c Checker) {
+		if wsChecker, ok := c.(*WebSocketChecker); ok {
+			wsChecker.response.expect = data
+		}
+	})
+}
+
+// WithWebSocketExpectPattern makes the WebSocketChecker wait for a message matching re.
+func WithWebSocketExpectPattern(re *regexp.Regexp) Option {
+	return OptionFunc(func(c Checker) {
+		if wsChecker, ok := c.(*WebSocketChecker); ok {
+			wsChecker.response.expectRegex = re
+		}
+	})
+}
@@ -0,0 +1,356 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeServerFrame writes an unmasked frame, as sent by a server.
+func writeServerFrame(w *bufio.Writer, opcode byte, fin bool, payload []byte) {
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	_ = w.WriteByte(first)
+	_ = w.WriteByte(byte(len(payload))) // Test payloads are shorter than 126 bytes
+	_, _ = w.Write(payload)
+	_ = w.Flush()
+}
+
+// newWebSocketServer returns a handler that upgrades the connection and answers every text message with the
+// messages returned by reply. The close handshake is answered unless skipClose is set.
+func newWebSocketServer(t *testing.T, reply func(message string) []string, skipClose bool) http.Handler {
+	t.Helper()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Authorization") == "Bearer invalid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+		_, _ = rw.WriteString("Sec-WebSocket-Accept: " + webSocketAccept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		_ = rw.Flush()
+
+		for {
+			opcode, _, payload, err := readWebSocketFrame(rw.Reader)
+			if err != nil {
+				return
+			}
+			switch opcode {
+			case wsOpText:
+				for _, message := range reply(string(payload)) {
+					writeServerFrame(rw.Writer, wsOpText, true, []byte(message))
+				}
+			case wsOpClose:
+				if !skipClose {
+					writeServerFrame(rw.Writer, wsOpClose, true, payload)
+				}
+				return
+			}
+		}
+	})
+}
+
+func TestNewWebSocketChecker(t *testing.T) {
+	t.Parallel()
+
+	checker, err := newWebSocketChecker("example", "wss://example.com/ws",
+		WithWebSocketHeaders(map[string]string{"Authorization": "Bearer token"}),
+		WithWebSocketSkipTLSVerify(true),
+		WithWebSocketTimeout(3*time.Second),
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "example", checker.Name())
+	assert.Equal(t, "wss://example.com/ws", checker.Address())
+	assert.Equal(t, WebSocket.String(), checker.Type())
+	assert.Equal(t, "Bearer token", checker.headers["Authorization"])
+	assert.True(t, checker.skipTLSVerify)
+	assert.Equal(t, 3*time.Second, checker.timeout)
+}
+
+func TestWebSocketChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	echo := func(message string) []string { return []string{message} }
+
+	t.Run("Upgrade And Close", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(newWebSocketServer(t, echo, false))
+		defer server.Close()
+
+		checker, err := newWebSocketChecker("gateway", "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Send And Expect Over TLS", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewTLSServer(newWebSocketServer(t, func(message string) []string {
+			if message == `{"type":"ping"}` {
+				return []string{`{"type":"welcome"}`, `{"type":"pong"}`}
+			}
+			return nil
+		}, false))
+		defer server.Close()
+
+		checker, err := newWebSocketChecker("gateway", "wss"+strings.TrimPrefix(server.URL, "https")+"/ws",
+			WithWebSocketSkipTLSVerify(true),
+			WithWebSocketSend([]byte(`{"type":"ping"}`)),
+			WithWebSocketExpectPattern(regexp.MustCompile(`"type":"pong"`)),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Untrusted Certificate", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewUnstartedServer(newWebSocketServer(t, echo, false))
+		server.Config.ErrorLog = log.New(io.Discard, "", 0) // Rejected handshakes are expected
+		server.StartTLS()
+		defer server.Close()
+
+		checker, err := newWebSocketChecker("gateway", "wss"+strings.TrimPrefix(server.URL, "https")+"/ws")
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "TLS handshake failed: ")
+	})
+
+	t.Run("Headers", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(newWebSocketServer(t, echo, false))
+		defer server.Close()
+
+		checker, err := newWebSocketChecker("gateway", "ws"+strings.TrimPrefix(server.URL, "http")+"/ws",
+			WithWebSocketHeaders(map[string]string{"Authorization": "Bearer invalid"}),
+		)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "upgrade rejected: got status code 401, expected 101")
+	})
+
+	t.Run("Upgrade Not Supported", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		checker, err := newWebSocketChecker("gateway", "ws"+strings.TrimPrefix(server.URL, "http")+"/health")
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "upgrade rejected: got status code 200, expected 101")
+	})
+
+	t.Run("Upgrade Headers Missing", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			headers string
+			err     string
+		}{
+			{headers: "Connection: Upgrade\r\n", err: `invalid Upgrade header ""`},
+			{headers: "Upgrade: h2c\r\nConnection: Upgrade\r\n", err: `invalid Upgrade header "h2c"`},
+			{headers: "Upgrade: WebSocket\r\n", err: `invalid Connection header ""`},
+			{headers: "Upgrade: websocket\r\nConnection: keep-alive\r\n", err: `invalid Connection header "keep-alive"`},
+		} {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conn, rw, err := http.NewResponseController(w).Hijack()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+
+				_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" + tc.headers)
+				_, _ = rw.WriteString("Sec-WebSocket-Accept: " + webSocketAccept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+				_ = rw.Flush()
+			}))
+
+			checker, err := newWebSocketChecker("gateway", "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+			assert.NoError(t, err)
+
+			err = checker.Check(context.Background())
+			assert.EqualError(t, err, tc.err)
+			server.Close()
+		}
+	})
+
+	t.Run("Connection Header With Multiple Tokens", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, rw, err := http.NewResponseController(w).Hijack()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: keep-alive, upgrade\r\n")
+			_, _ = rw.WriteString("Sec-WebSocket-Accept: " + webSocketAccept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+			_ = rw.Flush()
+
+			if opcode, _, payload, err := readWebSocketFrame(rw.Reader); err == nil && opcode == wsOpClose {
+				writeServerFrame(rw.Writer, wsOpClose, true, payload)
+			}
+		}))
+		defer server.Close()
+
+		checker, err := newWebSocketChecker("gateway", "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		assert.NoError(t, err)
+
+		assert.NoError(t, checker.Check(context.Background()))
+	})
+
+	t.Run("Unexpected Reply", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(newWebSocketServer(t, func(message string) []string { return []string{"hub unavailable"} }, false))
+		defer server.Close()
+
+		checker, err := newWebSocketChecker("gateway", "ws"+strings.TrimPrefix(server.URL, "http")+"/ws",
+			WithWebSocketSend([]byte("ping")),
+			WithWebSocketExpect([]byte("pong")),
+			WithWebSocketTimeout(200*time.Millisecond),
+		)
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `unexpected response, expected "pong": received 15 bytes "hub unavailable": `)
+		assert.Contains(t, err.Error(), "i/o timeout")
+	})
+
+	t.Run("Close Not Answered", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(newWebSocketServer(t, echo, true))
+		defer server.Close()
+
+		checker, err := newWebSocketChecker("gateway", "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, "failed to close connection cleanly: EOF")
+	})
+
+	t.Run("Invalid Scheme", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := newWebSocketChecker("gateway", "http://example.com/ws")
+		assert.NoError(t, err)
+
+		err = checker.Check(context.Background())
+		assert.EqualError(t, err, `unsupported scheme "http": must be ws or wss`)
+	})
+}
+
+func TestReadWebSocketMessage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Fragments And Ping", func(t *testing.T) {
+		t.Parallel()
+
+		client, server := net.Pipe()
+		defer client.Close()
+		defer server.Close()
+
+		// net.Pipe is synchronous, so the pong must be read before the next frame is written
+		pongs := make(chan string, 1)
+		readPong := func(conn net.Conn) string {
+			opcode, _, payload, err := readWebSocketFrame(conn)
+			if err != nil || opcode != wsOpPong {
+				return ""
+			}
+			return string(payload)
+		}
+
+		go func() {
+			w := bufio.NewWriter(server)
+			writeServerFrame(w, wsOpText, false, []byte("hel"))
+			writeServerFrame(w, wsOpPing, true, []byte("keepalive"))
+			pongs <- readPong(server)
+			writeServerFrame(w, wsOpContinuation, true, []byte("lo"))
+		}()
+
+		message, err := readWebSocketMessage(client, bufio.NewReader(client))
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", string(message))
+		assert.Equal(t, "keepalive", <-pongs)
+	})
+
+	t.Run("Close Frame", func(t *testing.T) {
+		t.Parallel()
+
+		client, server := net.Pipe()
+		defer client.Close()
+		defer server.Close()
+
+		go func() {
+			payload := binary.BigEndian.AppendUint16(nil, 1011)
+			writeServerFrame(bufio.NewWriter(server), wsOpClose, true, append(payload, "hub down"...))
+		}()
+
+		_, err := readWebSocketMessage(client, bufio.NewReader(client))
+		assert.EqualError(t, err, "connection closed by server with code 1011: hub down")
+	})
+}
+
+func TestWriteWebSocketFrame(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range []int{0, 125, 126, 70000} {
+		client, server := net.Pipe()
+		payload := []byte(strings.Repeat("a", size))
+
+		go func() {
+			_ = writeWebSocketFrame(client, wsOpBinary, payload)
+		}()
+
+		opcode, fin, received, err := readWebSocketFrame(server)
+		if size > maxWebSocketMessageSize {
+			assert.EqualError(t, err, "frame exceeds 65536 bytes")
+		} else {
+			assert.NoError(t, err)
+			assert.Equal(t, wsOpBinary, opcode)
+			assert.True(t, fin)
+			assert.Equal(t, payload, received)
+		}
+
+		_ = client.Close()
+		_ = server.Close()
+	}
+}
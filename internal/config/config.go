@@ -14,6 +14,7 @@ import (
 
 const (
 	paramDefaultInterval             string        = "default-interval"
+	paramDryRun                      string        = "dry-run"
 	defaultCheckInterval             time.Duration = 2 * time.Second
 	defaultHTTPAllowDuplicateHeaders bool          = false
 	defaultHTTPSkipTLSVerify         bool          = false
@@ -39,6 +40,7 @@ type ParsedFlags struct {
 	ShowVersion          bool
 	Version              string
 	DefaultCheckInterval time.Duration
+	DryRun               bool
 	DynFlags             *dynflags.DynFlags
 }
 
@@ -78,8 +80,14 @@ func ParseFlags(args []string, version string, w io.Writer) (*ParsedFlags, error
 		return nil, err
 	}
 
+	dryRun, err := fs.GetBool(paramDryRun)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ParsedFlags{
 		DefaultCheckInterval: defaultInterval,
+		DryRun:               dryRun,
 		DynFlags:             df,
 	}, nil
 }
@@ -90,13 +98,14 @@ func setupGlobalFlags() *flag.FlagSet {
 	fs.SortFlags = false
 
 	fs.Duration(paramDefaultInterval, defaultCheckInterval, "Default interval between checks. Can be overridden for each target.")
+	fs.Bool(paramDryRun, false, "Validate the configuration, print the resolved targets and exit without checking them.")
 	fs.Bool("version", false, "Show version and exit.")
 	fs.BoolP("help", "h", false, "Show help.")
 
 	return fs
 }
 
-// setupDynamicFlags sets up dynamic flags for HTTP, TCP, ICMP.
+// setupDynamicFlags sets up dynamic flags for HTTP, TCP, ICMP, TLS, UDP, unix sockets, files, commands, WebSockets, MongoDB, Kafka, AMQP, MQTT and NATS.
 func setupDynamicFlags() *dynflags.DynFlags {
 	df := dynflags.New(dynflags.ContinueOnError)
 	df.Epilog("For more information, see https://github.com/containeroo/portpatrol")
@@ -110,10 +119,25 @@ func setupDynamicFlags() *dynflags.DynFlags {
 	http.String("address", "", "HTTP target URL")
 	http.Duration("interval", 1*time.Second, "Time between HTTP requests. Can be overwritten with --default-interval.")
 	http.StringSlices("header", nil, "HTTP headers to send")
+	http.String("body", "", "HTTP request body to send")
+	http.String("content-type", "", "Content-Type of the HTTP request body")
 	http.Bool("allow-duplicate-headers", defaultHTTPAllowDuplicateHeaders, "Allow duplicate HTTP headers")
+	http.String("basic-auth-user", "", "Username for HTTP basic authentication")
+	http.String("basic-auth-password", "", "Password for HTTP basic authentication")
+	http.String("oauth2-token-url", "", "OAuth2 token endpoint for the client credentials grant")
+	http.String("oauth2-client-id", "", "OAuth2 client ID")
+	http.String("oauth2-client-secret", "", "OAuth2 client secret")
+	http.String("oauth2-scopes", "", "Comma-separated list of OAuth2 scopes to request")
+	http.Bool("oauth2-skip-tls-verify", false, "Skip TLS verification of the OAuth2 token endpoint")
 	http.String("expected-status-codes", "200", "Expected HTTP status codes")
+	http.StringSlices("expected-header", nil, "Expected HTTP response header in Name=regex format")
 	http.Bool("skip-tls-verify", defaultHTTPSkipTLSVerify, "Skip TLS verification")
+	http.String("follow-redirects", "true", "Follow redirects: true, false or max=N")
+	http.Duration("max-response-time", 0, "Maximum response time before the target is considered not ready (0 disables the check)")
 	http.Duration("timeout", 2*time.Second, "Timeout in seconds")
+	http.StringSlices("resolve", nil, "Connect to IP instead of resolving host, in host:port:ip format")
+	http.Bool("all-addresses", false, "Check every resolved IP address of the host")
+	http.Int("min-addresses", 0, "Minimum number of reachable addresses with all-addresses (0 requires all)")
 
 	// ICMP flags
 	icmp := df.Group("icmp")
@@ -122,6 +146,16 @@ func setupDynamicFlags() *dynflags.DynFlags {
 	icmp.Duration("interval", 1*time.Second, "Time between ICMP requests. Can be overwritten with --default-interval.")
 	icmp.Duration("read-timeout", 2*time.Second, "Timeout for ICMP read")
 	icmp.Duration("write-timeout", 2*time.Second, "Timeout for ICMP write")
+	icmp.Int("count", 1, "Number of echo requests sent per check")
+	icmp.Int("max-loss", 0, "Maximum packet loss in percent")
+	icmp.Duration("max-rtt", 0, "Maximum round-trip time (0 disables the limit)")
+	icmp.Int("rtt-percentile", 0, "Percentile of the round-trip times compared with max-rtt (0 uses the average)")
+	icmp.Int("size", 0, "Payload size of echo requests in bytes (0 uses the default 15 byte payload)")
+	icmp.Int("ttl", 0, "Time to live (hop limit for IPv6) of echo requests (0 uses the system default)")
+	icmp.Bool("dont-fragment", false, "Send echo requests that must not be fragmented (Linux only)")
+	icmp.String("privileged", "auto", "Use raw ICMP sockets (true), unprivileged datagram sockets (false) or detect it (auto)")
+	icmp.Bool("all-addresses", false, "Check every resolved IP address of the host")
+	icmp.Int("min-addresses", 0, "Minimum number of reachable addresses with all-addresses (0 requires all)")
 
 	// TCP flags
 	tcp := df.Group("tcp")
@@ -129,6 +163,152 @@ func setupDynamicFlags() *dynflags.DynFlags {
 	tcp.String("address", "", "TCP target address")
 	tcp.Duration("timeout", 2*time.Second, "Timeout for TCP connection")
 	tcp.Duration("interval", 1*time.Second, "Time between TCP requests. Can be overwritten with --default-interval.")
+	tcp.StringSlices("resolve", nil, "Connect to IP instead of resolving host, in host:port:ip format")
+	tcp.String("send", "", "Data to send after connecting, supports escape sequences like \\r\\n")
+	tcp.String("expect", "", "Expected response after connecting, supports escape sequences like \\r\\n")
+	tcp.Bool("expect-regex", false, "Treat expect as a regular expression")
+	tcp.Duration("read-timeout", 2*time.Second, "Timeout for sending data and receiving the expected response")
+	tcp.Bool("all-addresses", false, "Check every resolved IP address of the host")
+	tcp.Int("min-addresses", 0, "Minimum number of reachable addresses with all-addresses (0 requires all)")
+
+	// TLS flags
+	tls := df.Group("tls")
+	tls.String("name", "", "Name of the TLS checker")
+	tls.String("address", "", "TLS target address in host:port format")
+	tls.Duration("interval", 1*time.Second, "Time between TLS handshakes. Can be overwritten with --default-interval.")
+	tls.Duration("timeout", 2*time.Second, "Timeout for connecting and completing the handshake")
+	tls.String("server-name", "", "Server name for SNI and certificate verification (defaults to the host of the address)")
+	tls.String("ca-file", "", "PEM file with CA certificates to verify the chain instead of the system CAs")
+	tls.Duration("min-validity", 0, "Minimum remaining validity of the certificate (e.g. 168h)")
+	tls.String("expected-san", "", "DNS name or IP address the certificate must be valid for")
+	tls.String("expected-fingerprint", "", "Expected SHA-256 fingerprint of the certificate in hex")
+
+	// UDP flags
+	udp := df.Group("udp")
+	udp.String("name", "", "Name of the UDP checker")
+	udp.String("address", "", "UDP target address in host:port format")
+	udp.Duration("interval", 1*time.Second, "Time between UDP requests. Can be overwritten with --default-interval.")
+	udp.Duration("timeout", 2*time.Second, "Timeout for sending the datagram and receiving the reply")
+	udp.String("send", "", "Datagram to send, supports escape sequences like \\r\\n")
+	udp.String("expect", "", "Expected reply, supports escape sequences like \\r\\n (any reply is accepted if empty)")
+	udp.Bool("expect-regex", false, "Treat expect as a regular expression")
+
+	// Unix flags
+	unix := df.Group("unix")
+	unix.String("name", "", "Name of the unix socket checker")
+	unix.String("address", "", "Path of the unix socket, optionally prefixed with unix://")
+	unix.Duration("interval", 1*time.Second, "Time between connection attempts. Can be overwritten with --default-interval.")
+	unix.Duration("timeout", 1*time.Second, "Timeout for connecting to the socket")
+	unix.String("socket-type", "stream", "Type of the socket: stream or datagram")
+
+	// File flags
+	file := df.Group("file")
+	file.String("name", "", "Name of the file checker")
+	file.String("address", "", "Path of the file or directory")
+	file.Duration("interval", 1*time.Second, "Time between checks. Can be overwritten with --default-interval.")
+	file.Bool("non-empty", false, "Require the file to have content or the directory to have entries")
+	file.String("content-regex", "", "Regular expression the file content must match")
+	file.String("json-path", "", "Dot separated path that must exist in the JSON content (e.g. status.ready)")
+	file.String("json-value", "", "Value expected at json-path")
+	file.String("permissions", "", "Exact permissions the file must have in octal (e.g. 0600)")
+	file.Bool("modified-after-start", false, "Require the file to be modified after startup")
+
+	// Exec flags
+	exec := df.Group("exec")
+	exec.String("name", "", "Name of the exec checker")
+	exec.String("address", "", "Command to run")
+	exec.Duration("interval", 1*time.Second, "Time between command runs. Can be overwritten with --default-interval.")
+	exec.StringSlices("arg", nil, "Argument passed to the command. Can be specified multiple times.")
+	exec.StringSlices("env", nil, "Environment variable in KEY=VALUE format. Can be specified multiple times.")
+	exec.Duration("timeout", 5*time.Second, "Timeout after which the command is killed")
+	exec.String("exit-codes", "0", "Exit codes treated as ready (e.g. 0,3 or 0-2)")
+	exec.String("expect", "", "Expected output on stdout, supports escape sequences like \\r\\n")
+	exec.Bool("expect-regex", false, "Treat expect as a regular expression")
+
+	// WebSocket flags
+	websocket := df.Group("websocket")
+	websocket.String("name", "", "Name of the WebSocket checker")
+	websocket.String("address", "", "WebSocket target URL (ws:// or wss://)")
+	websocket.Duration("interval", 1*time.Second, "Time between WebSocket checks. Can be overwritten with --default-interval.")
+	websocket.StringSlices("header", nil, "HTTP header sent with the upgrade request. Can be specified multiple times.")
+	websocket.Bool("allow-duplicate-headers", defaultHTTPAllowDuplicateHeaders, "Allow duplicate HTTP headers")
+	websocket.Bool("skip-tls-verify", false, "Skip TLS verification")
+	websocket.Duration("timeout", 2*time.Second, "Timeout for the upgrade, the message exchange and the close handshake")
+	websocket.String("send", "", "Text message sent after the upgrade, supports escape sequences like \\r\\n")
+	websocket.String("expect", "", "Expected reply, supports escape sequences like \\r\\n")
+	websocket.Bool("expect-regex", false, "Treat expect as a regular expression")
+
+	// MongoDB flags
+	mongodb := df.Group("mongodb")
+	mongodb.String("name", "", "Name of the MongoDB checker")
+	mongodb.String("address", "", "MongoDB target address in host:port format")
+	mongodb.Duration("interval", 1*time.Second, "Time between MongoDB checks. Can be overwritten with --default-interval.")
+	mongodb.Duration("timeout", 2*time.Second, "Timeout for connecting, authenticating and running hello")
+	mongodb.String("role", "any", "Required member state: any, primary (writable primary) or secondary")
+	mongodb.String("replica-set", "", "Required replica set name")
+	mongodb.String("username", "", "Username for SCRAM authentication")
+	mongodb.String("password", "", "Password for SCRAM authentication")
+	mongodb.String("auth-source", "admin", "Database holding the user")
+	mongodb.String("auth-mechanism", "SCRAM-SHA-256", "Authentication mechanism: SCRAM-SHA-256 or SCRAM-SHA-1")
+	mongodb.Bool("tls", false, "Connect with TLS")
+	mongodb.Bool("skip-tls-verify", false, "Skip TLS verification")
+	mongodb.String("ca-file", "", "PEM file with CA certificates to verify the server certificate instead of the system CAs")
+
+	// Kafka flags
+	kafka := df.Group("kafka")
+	kafka.String("name", "", "Name of the Kafka checker")
+	kafka.String("address", "", "Kafka broker address in host:port format")
+	kafka.Duration("interval", 1*time.Second, "Time between Kafka checks. Can be overwritten with --default-interval.")
+	kafka.Duration("timeout", 2*time.Second, "Timeout for connecting, authenticating and the metadata requests")
+	kafka.String("topic", "", "Topic that must exist with a leader for every partition")
+	kafka.String("username", "", "Username for SASL/PLAIN authentication")
+	kafka.String("password", "", "Password for SASL/PLAIN authentication")
+	kafka.Bool("tls", false, "Connect with TLS")
+	kafka.Bool("skip-tls-verify", false, "Skip TLS verification")
+	kafka.String("ca-file", "", "PEM file with CA certificates to verify the server certificate instead of the system CAs")
+
+	// AMQP flags
+	amqp := df.Group("amqp")
+	amqp.String("name", "", "Name of the AMQP checker")
+	amqp.String("address", "", "AMQP broker address in host:port format")
+	amqp.Duration("interval", 1*time.Second, "Time between AMQP checks. Can be overwritten with --default-interval.")
+	amqp.Duration("timeout", 2*time.Second, "Timeout for connecting and the whole handshake")
+	amqp.String("username", "", "Username for PLAIN authentication (defaults to guest)")
+	amqp.String("password", "", "Password for PLAIN authentication (defaults to guest)")
+	amqp.String("vhost", "/", "Virtual host to open")
+	amqp.String("queue", "", "Queue that must exist in the virtual host")
+	amqp.Bool("tls", false, "Connect with TLS")
+	amqp.Bool("skip-tls-verify", false, "Skip TLS verification")
+	amqp.String("ca-file", "", "PEM file with CA certificates to verify the server certificate instead of the system CAs")
+
+	// MQTT flags
+	mqtt := df.Group("mqtt")
+	mqtt.String("name", "", "Name of the MQTT checker")
+	mqtt.String("address", "", "MQTT broker URL (mqtt:// or mqtts://)")
+	mqtt.Duration("interval", 1*time.Second, "Time between MQTT checks. Can be overwritten with --default-interval.")
+	mqtt.Duration("timeout", 2*time.Second, "Timeout for connecting and the whole check")
+	mqtt.String("protocol-version", "3.1.1", "MQTT protocol version: 3.1.1 or 5")
+	mqtt.String("client-id", "", "Client identifier (default: portpatrol- with a random suffix)")
+	mqtt.String("username", "", "Username sent in CONNECT")
+	mqtt.String("password", "", "Password sent in CONNECT")
+	mqtt.Bool("skip-tls-verify", false, "Skip TLS verification")
+	mqtt.String("ca-file", "", "PEM file with CA certificates to verify the broker certificate instead of the system CAs")
+	mqtt.String("topic", "", "Topic to subscribe and publish a test message to")
+
+	// NATS flags
+	nats := df.Group("nats")
+	nats.String("name", "", "Name of the NATS checker")
+	nats.String("address", "", "NATS server address in host:port format")
+	nats.Duration("interval", 1*time.Second, "Time between NATS checks. Can be overwritten with --default-interval.")
+	nats.Duration("timeout", 2*time.Second, "Timeout for connecting and the whole check")
+	nats.String("username", "", "Username sent in CONNECT")
+	nats.String("password", "", "Password sent in CONNECT")
+	nats.String("token", "", "Authentication token sent in CONNECT")
+	nats.Bool("jetstream", false, "Require JetStream to be available")
+	nats.String("stream", "", "JetStream stream that must exist")
+	nats.Bool("tls", false, "Connect with TLS")
+	nats.Bool("skip-tls-verify", false, "Skip TLS verification")
+	nats.String("ca-file", "", "PEM file with CA certificates to verify the server certificate instead of the system CAs")
 
 	return df
 }
@@ -43,6 +43,17 @@ func TestParseFlags(t *testing.T) {
 		assert.Contains(t, output.String(), "Usage: portpatrol [FLAGS] [DYNAMIC FLAGS..]")
 	})
 
+	t.Run("Dry Run Flag", func(t *testing.T) {
+		t.Parallel()
+
+		args := []string{"--dry-run"}
+		var output strings.Builder
+
+		parsedFlags, err := ParseFlags(args, "1.0.0", &output)
+		assert.NoError(t, err)
+		assert.True(t, parsedFlags.DryRun)
+	})
+
 	t.Run("Show Version Flag", func(t *testing.T) {
 		t.Parallel()
 
@@ -84,6 +95,7 @@ func TestSetupGlobalFlags(t *testing.T) {
 
 	flagSet := setupGlobalFlags()
 	assert.NotNil(t, flagSet.Lookup("default-interval"))
+	assert.NotNil(t, flagSet.Lookup("dry-run"))
 	assert.NotNil(t, flagSet.Lookup("version"))
 	assert.NotNil(t, flagSet.Lookup("help"))
 }
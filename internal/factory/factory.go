@@ -1,16 +1,33 @@
 package factory
 
 import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/containeroo/dynflags"
 	"github.com/containeroo/httputils"
 	"github.com/containeroo/portpatrol/internal/checker"
+	"github.com/containeroo/portpatrol/internal/redact"
 	"github.com/containeroo/resolver"
 )
 
+// defaultMaxRedirects is the number of redirects followed with "follow-redirects=true".
+const defaultMaxRedirects int = 10
+
+// maxICMPPayloadSize is the largest echo payload fitting into an IPv4 packet (65535 - 20 byte IP header - 8 byte ICMP header).
+const maxICMPPayloadSize int = 65507
+
 // CheckerWithInterval represents a checker with its interval.
 type CheckerWithInterval struct {
 	Interval time.Duration
@@ -29,15 +46,17 @@ func BuildCheckers(dynFlags *dynflags.DynFlags, defaultInterval time.Duration) (
 		}
 
 		// Process each parsed group (child) under the parent group
-		for _, group := range childGroups {
+		for _, parsedGroup := range childGroups {
+			group := newPropertyGroup(parsedGroup)
+
 			address, err := group.GetString("address")
 			if err != nil {
-				return nil, fmt.Errorf("missing address for %s checker: %w", parentName, err)
+				return nil, fmt.Errorf("missing address for %s checker \"--%s.%s.address\": %w", parentName, parentName, group.Name, err)
 			}
 
 			resolvedAddress, err := resolver.ResolveVariable(address)
 			if err != nil {
-				return nil, fmt.Errorf("failed to resolve variable in address: %w", err)
+				return nil, fmt.Errorf("invalid \"--%s.%s.address\": failed to resolve variable: %w", parentName, group.Name, err)
 			}
 
 			// Default interval for the checker
@@ -64,6 +83,37 @@ func BuildCheckers(dynFlags *dynflags.DynFlags, defaultInterval time.Duration) (
 					opts = append(opts, checker.WithHTTPHeaders(headersMap))
 				}
 
+				body, _ := group.GetString("body")
+				if body != "" {
+					resolvedBody, err := resolveSecret(body, false)
+					if err != nil {
+						return nil, fmt.Errorf("invalid \"--%s.%s.body\": failed to resolve variable: %w", parentName, group.Name, err)
+					}
+					opts = append(opts, checker.WithHTTPBody([]byte(resolvedBody)))
+				}
+
+				// The Content-Type header is only sent with a body
+				if contentType, err := group.GetString("content-type"); err == nil && contentType != "" {
+					if body == "" {
+						return nil, fmt.Errorf("invalid \"--%s.%s.content-type\": requires \"--%s.%s.body\"", parentName, group.Name, parentName, group.Name)
+					}
+					opts = append(opts, checker.WithHTTPContentType(contentType))
+				}
+
+				if expectedHeaders, err := group.GetStringSlices("expected-header"); err == nil {
+					expectedHeadersMap, err := createExpectedHeadersMap(expectedHeaders)
+					if err != nil {
+						return nil, fmt.Errorf("invalid \"--%s.%s.expected-header\": %w", parentName, group.Name, err)
+					}
+					opts = append(opts, checker.WithHTTPExpectedHeaders(expectedHeadersMap))
+				}
+
+				authOpts, err := buildHTTPAuthOptions(parentName, group)
+				if err != nil {
+					return nil, err
+				}
+				opts = append(opts, authOpts...)
+
 				if allowedStatusCodes, err := group.GetString("expected-status-codes"); err == nil {
 					statusCodes, err := httputils.ParseStatusCodes(allowedStatusCodes)
 					if err != nil {
@@ -77,13 +127,77 @@ func BuildCheckers(dynFlags *dynflags.DynFlags, defaultInterval time.Duration) (
 					opts = append(opts, checker.WithHTTPSkipTLSVerify(skipTLS))
 				}
 
+				if followRedirects, err := group.GetString("follow-redirects"); err == nil {
+					maxRedirects, err := parseFollowRedirects(followRedirects)
+					if err != nil {
+						return nil, fmt.Errorf("invalid \"--%s.%s.follow-redirects\": %w", parentName, group.Name, err)
+					}
+					opts = append(opts, checker.WithHTTPMaxRedirects(maxRedirects))
+				}
+
+				if maxResponseTime, err := group.GetDuration("max-response-time"); err == nil {
+					opts = append(opts, checker.WithHTTPMaxResponseTime(maxResponseTime))
+				}
+
 				if timeout, err := group.GetDuration("timeout"); err == nil {
 					opts = append(opts, checker.WithHTTPTimeout(timeout))
 				}
 
+				if resolve, err := group.GetStringSlices("resolve"); err == nil {
+					overrides, err := parseResolveOverrides(resolve)
+					if err != nil {
+						return nil, fmt.Errorf("invalid \"--%s.%s.resolve\": %w", parentName, group.Name, err)
+					}
+					opts = append(opts, checker.WithHTTPResolve(overrides))
+				}
+
+				allAddressesOpt, err := buildAllAddressesOption(parentName, group, checker.WithHTTPAllAddresses)
+				if err != nil {
+					return nil, err
+				}
+				if allAddressesOpt != nil {
+					opts = append(opts, allAddressesOpt)
+				}
+
 			case checker.TCP:
 				if timeout, err := group.GetDuration("timeout"); err == nil {
-					opts = append(opts, checker.WithHTTPTimeout(timeout)) // Could have a TCP-specific timeout option
+					opts = append(opts, checker.WithTCPTimeout(timeout))
+				}
+
+				if send, err := group.GetString("send"); err == nil && send != "" {
+					data, err := unescape(send)
+					if err != nil {
+						return nil, fmt.Errorf("invalid \"--%s.%s.send\": %w", parentName, group.Name, err)
+					}
+					opts = append(opts, checker.WithTCPSend([]byte(data)))
+				}
+
+				expectOpt, err := buildExpectOption(parentName, group, checker.WithTCPExpect, checker.WithTCPExpectPattern)
+				if err != nil {
+					return nil, err
+				}
+				if expectOpt != nil {
+					opts = append(opts, expectOpt)
+				}
+
+				if readTimeout, err := group.GetDuration("read-timeout"); err == nil {
+					opts = append(opts, checker.WithTCPReadTimeout(readTimeout))
+				}
+
+				if resolve, err := group.GetStringSlices("resolve"); err == nil {
+					overrides, err := parseResolveOverrides(resolve)
+					if err != nil {
+						return nil, fmt.Errorf("invalid \"--%s.%s.resolve\": %w", parentName, group.Name, err)
+					}
+					opts = append(opts, checker.WithTCPResolve(overrides))
+				}
+
+				allAddressesOpt, err := buildAllAddressesOption(parentName, group, checker.WithTCPAllAddresses)
+				if err != nil {
+					return nil, err
+				}
+				if allAddressesOpt != nil {
+					opts = append(opts, allAddressesOpt)
 				}
 
 			case checker.ICMP:
@@ -93,6 +207,158 @@ func BuildCheckers(dynFlags *dynflags.DynFlags, defaultInterval time.Duration) (
 				if writeTimeout, err := group.GetDuration("write-timeout"); err == nil {
 					opts = append(opts, checker.WithICMPWriteTimeout(writeTimeout))
 				}
+				if count, err := group.GetInt("count"); err == nil {
+					if count < 1 {
+						return nil, fmt.Errorf("invalid \"--%s.%s.count\": must be at least 1", parentName, group.Name)
+					}
+					opts = append(opts, checker.WithICMPCount(count))
+				}
+				if maxLoss, err := group.GetInt("max-loss"); err == nil {
+					if maxLoss < 0 || maxLoss > 100 {
+						return nil, fmt.Errorf("invalid \"--%s.%s.max-loss\": must be between 0 and 100", parentName, group.Name)
+					}
+					opts = append(opts, checker.WithICMPMaxLoss(maxLoss))
+				}
+				maxRTT, _ := group.GetDuration("max-rtt")
+				percentile, _ := group.GetInt("rtt-percentile")
+				if percentile < 0 || percentile > 100 {
+					return nil, fmt.Errorf("invalid \"--%s.%s.rtt-percentile\": must be between 0 and 100", parentName, group.Name)
+				}
+				if percentile > 0 && maxRTT == 0 {
+					return nil, fmt.Errorf("invalid \"--%s.%s.rtt-percentile\": requires \"--%s.%s.max-rtt\"", parentName, group.Name, parentName, group.Name)
+				}
+				if maxRTT != 0 {
+					opts = append(opts, checker.WithICMPMaxRTT(maxRTT, percentile))
+				}
+				if size, err := group.GetInt("size"); err == nil {
+					if size < 0 || size > maxICMPPayloadSize {
+						return nil, fmt.Errorf("invalid \"--%s.%s.size\": must be between 0 and %d", parentName, group.Name, maxICMPPayloadSize)
+					}
+					opts = append(opts, checker.WithICMPPacketSize(size))
+				}
+				if ttl, err := group.GetInt("ttl"); err == nil {
+					if ttl < 0 || ttl > 255 {
+						return nil, fmt.Errorf("invalid \"--%s.%s.ttl\": must be between 0 and 255", parentName, group.Name)
+					}
+					opts = append(opts, checker.WithICMPTTL(ttl))
+				}
+				if dontFragment, err := group.GetBool("dont-fragment"); err == nil {
+					opts = append(opts, checker.WithICMPDontFragment(dontFragment))
+				}
+				if privileged, err := group.GetString("privileged"); err == nil {
+					privilegedOpt, err := parsePrivileged(privileged)
+					if err != nil {
+						return nil, fmt.Errorf("invalid \"--%s.%s.privileged\": %w", parentName, group.Name, err)
+					}
+					if privilegedOpt != nil {
+						opts = append(opts, privilegedOpt)
+					}
+				}
+
+				allAddressesOpt, err := buildAllAddressesOption(parentName, group, checker.WithICMPAllAddresses)
+				if err != nil {
+					return nil, err
+				}
+				if allAddressesOpt != nil {
+					opts = append(opts, allAddressesOpt)
+				}
+
+			case checker.TLS:
+				tlsOpts, err := buildTLSOptions(parentName, group)
+				if err != nil {
+					return nil, err
+				}
+				opts = append(opts, tlsOpts...)
+
+			case checker.Unix:
+				if timeout, err := group.GetDuration("timeout"); err == nil {
+					opts = append(opts, checker.WithUnixTimeout(timeout))
+				}
+
+				if socketType, err := group.GetString("socket-type"); err == nil {
+					datagram, err := parseSocketType(socketType)
+					if err != nil {
+						return nil, fmt.Errorf("invalid \"--%s.%s.socket-type\": %w", parentName, group.Name, err)
+					}
+					opts = append(opts, checker.WithUnixDatagram(datagram))
+				}
+
+			case checker.File:
+				fileOpts, err := buildFileOptions(parentName, group)
+				if err != nil {
+					return nil, err
+				}
+				opts = append(opts, fileOpts...)
+
+			case checker.Exec:
+				execOpts, err := buildExecOptions(parentName, group)
+				if err != nil {
+					return nil, err
+				}
+				opts = append(opts, execOpts...)
+
+			case checker.WebSocket:
+				wsOpts, err := buildWebSocketOptions(parentName, group)
+				if err != nil {
+					return nil, err
+				}
+				opts = append(opts, wsOpts...)
+
+			case checker.MongoDB:
+				mongoOpts, err := buildMongoDBOptions(parentName, group)
+				if err != nil {
+					return nil, err
+				}
+				opts = append(opts, mongoOpts...)
+
+			case checker.Kafka:
+				kafkaOpts, err := buildKafkaOptions(parentName, group)
+				if err != nil {
+					return nil, err
+				}
+				opts = append(opts, kafkaOpts...)
+
+			case checker.AMQP:
+				amqpOpts, err := buildAMQPOptions(parentName, group)
+				if err != nil {
+					return nil, err
+				}
+				opts = append(opts, amqpOpts...)
+
+			case checker.MQTT:
+				mqttOpts, err := buildMQTTOptions(parentName, group, resolvedAddress)
+				if err != nil {
+					return nil, err
+				}
+				opts = append(opts, mqttOpts...)
+
+			case checker.NATS:
+				natsOpts, err := buildNATSOptions(parentName, group)
+				if err != nil {
+					return nil, err
+				}
+				opts = append(opts, natsOpts...)
+
+			case checker.UDP:
+				if timeout, err := group.GetDuration("timeout"); err == nil {
+					opts = append(opts, checker.WithUDPTimeout(timeout))
+				}
+
+				if send, err := group.GetString("send"); err == nil && send != "" {
+					data, err := unescape(send)
+					if err != nil {
+						return nil, fmt.Errorf("invalid \"--%s.%s.send\": %w", parentName, group.Name, err)
+					}
+					opts = append(opts, checker.WithUDPSend([]byte(data)))
+				}
+
+				expectOpt, err := buildExpectOption(parentName, group, checker.WithUDPExpect, checker.WithUDPExpectPattern)
+				if err != nil {
+					return nil, err
+				}
+				if expectOpt != nil {
+					opts = append(opts, expectOpt)
+				}
 			}
 
 			name, _ := group.GetString("name")
@@ -100,9 +366,13 @@ func BuildCheckers(dynFlags *dynflags.DynFlags, defaultInterval time.Duration) (
 				name = group.Name
 			}
 
+			if err := validateConsumed(parentName, group); err != nil {
+				return nil, err
+			}
+
 			instance, err := checker.NewChecker(checkType, name, resolvedAddress, opts...)
 			if err != nil {
-				return nil, fmt.Errorf("failed to create %s checker: %w", parentName, err)
+				return nil, fmt.Errorf("failed to create %s checker \"%s\": %w", parentName, group.Name, err)
 			}
 
 			// Wrap the checker with its interval and add to the list
@@ -116,6 +386,799 @@ func BuildCheckers(dynFlags *dynflags.DynFlags, defaultInterval time.Duration) (
 	return checkers, nil
 }
 
+// buildAllAddressesOption creates the option to check every resolved address, or returns nil if it is disabled.
+func buildAllAddressesOption(parentName string, group *propertyGroup, withAllAddresses func(int) checker.Option) (checker.Option, error) {
+	allAddresses, _ := group.GetBool("all-addresses")
+	minAddresses, _ := group.GetInt("min-addresses")
+
+	if minAddresses < 0 {
+		return nil, fmt.Errorf("invalid \"--%s.%s.min-addresses\": must not be negative", parentName, group.Name)
+	}
+
+	if !allAddresses {
+		if minAddresses > 0 {
+			return nil, fmt.Errorf("invalid \"--%s.%s.min-addresses\": requires \"--%s.%s.all-addresses\"", parentName, group.Name, parentName, group.Name)
+		}
+		return nil, nil
+	}
+
+	return withAllAddresses(minAddresses), nil
+}
+
+// buildHTTPAuthOptions creates the options for HTTP basic authentication or the OAuth2 client credentials grant.
+func buildHTTPAuthOptions(parentName string, group *propertyGroup) ([]checker.Option, error) {
+	var opts []checker.Option
+
+	user, _ := group.GetString("basic-auth-user")
+	password, _ := group.GetString("basic-auth-password")
+	tokenURL, _ := group.GetString("oauth2-token-url")
+	clientID, _ := group.GetString("oauth2-client-id")
+	clientSecret, _ := group.GetString("oauth2-client-secret")
+	scopes, _ := group.GetString("oauth2-scopes")
+	oauth2SkipTLSVerify, _ := group.GetBool("oauth2-skip-tls-verify") // Type is checked when parsing
+
+	if user != "" || password != "" {
+		resolvedUser, err := resolveSecret(user, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid \"--%s.%s.basic-auth-user\": failed to resolve variable: %w", parentName, group.Name, err)
+		}
+		resolvedPassword, err := resolveSecret(password, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid \"--%s.%s.basic-auth-password\": failed to resolve variable: %w", parentName, group.Name, err)
+		}
+		if resolvedUser == "" {
+			return nil, fmt.Errorf("invalid \"--%s.%s.basic-auth-user\": user is required when a password is set", parentName, group.Name)
+		}
+		opts = append(opts, checker.WithHTTPBasicAuth(resolvedUser, resolvedPassword))
+	}
+
+	if tokenURL == "" && clientID == "" && clientSecret == "" && scopes == "" {
+		if oauth2SkipTLSVerify {
+			return nil, fmt.Errorf("invalid \"--%s.%s.oauth2-skip-tls-verify\": requires \"--%s.%s.oauth2-token-url\"", parentName, group.Name, parentName, group.Name)
+		}
+		return opts, nil
+	}
+
+	if len(opts) > 0 {
+		return nil, fmt.Errorf("invalid \"--%s.%s.oauth2-token-url\": OAuth2 cannot be combined with basic authentication", parentName, group.Name)
+	}
+
+	if tokenURL == "" {
+		return nil, fmt.Errorf("missing \"--%s.%s.oauth2-token-url\": required for OAuth2 client credentials", parentName, group.Name)
+	}
+
+	resolvedTokenURL, err := resolver.ResolveVariable(tokenURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid \"--%s.%s.oauth2-token-url\": failed to resolve variable: %w", parentName, group.Name, err)
+	}
+	resolvedClientID, err := resolveSecret(clientID, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid \"--%s.%s.oauth2-client-id\": failed to resolve variable: %w", parentName, group.Name, err)
+	}
+	resolvedClientSecret, err := resolveSecret(clientSecret, true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid \"--%s.%s.oauth2-client-secret\": failed to resolve variable: %w", parentName, group.Name, err)
+	}
+
+	if resolvedClientID == "" {
+		return nil, fmt.Errorf("missing \"--%s.%s.oauth2-client-id\": required for OAuth2 client credentials", parentName, group.Name)
+	}
+
+	var scopeList []string
+	for _, scope := range strings.Split(scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopeList = append(scopeList, scope)
+		}
+	}
+
+	opts = append(opts,
+		checker.WithHTTPOAuth2ClientCredentials(resolvedTokenURL, resolvedClientID, resolvedClientSecret, scopeList),
+		checker.WithHTTPOAuth2SkipTLSVerify(oauth2SkipTLSVerify),
+	)
+
+	return opts, nil
+}
+
+// createExpectedHeadersMap creates a map of header names to compiled patterns from a slice of "Name=regex" strings.
+func createExpectedHeadersMap(headers []string) (map[string]*regexp.Regexp, error) {
+	headersMap := make(map[string]*regexp.Regexp)
+
+	for _, header := range headers {
+		name, pattern, found := strings.Cut(header, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid expected header format: %q", header)
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for header %q: %w", name, err)
+		}
+
+		headersMap[http.CanonicalHeaderKey(name)] = re
+	}
+
+	return headersMap, nil
+}
+
+// parseResolveOverrides parses curl-style "host:port:ip" entries into a map of "host:port" to IP.
+// The IP may be an IPv6 address, optionally enclosed in brackets.
+func parseResolveOverrides(entries []string) (map[string]string, error) {
+	overrides := make(map[string]string)
+
+	for _, entry := range entries {
+		host, rest, found := strings.Cut(entry, ":")
+		if !found || host == "" {
+			return nil, fmt.Errorf("invalid resolve format, expected host:port:ip: %q", entry)
+		}
+
+		port, ip, found := strings.Cut(rest, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid resolve format, expected host:port:ip: %q", entry)
+		}
+
+		if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+			return nil, fmt.Errorf("invalid port in resolve entry: %q", entry)
+		}
+
+		ip = strings.TrimSuffix(strings.TrimPrefix(ip, "["), "]")
+		if net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("invalid IP in resolve entry: %q", entry)
+		}
+
+		overrides[net.JoinHostPort(strings.ToLower(host), port)] = ip
+	}
+
+	return overrides, nil
+}
+
+// parseFollowRedirects parses the redirect policy ("true", "false" or "max=N") into the maximum number of redirects.
+func parseFollowRedirects(value string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true":
+		return defaultMaxRedirects, nil
+	case "false":
+		return 0, nil
+	}
+
+	limit, found := strings.CutPrefix(strings.TrimSpace(value), "max=")
+	if !found {
+		return 0, fmt.Errorf("must be true, false or max=N: %q", value)
+	}
+
+	maxRedirects, err := strconv.Atoi(limit)
+	if err != nil || maxRedirects < 0 {
+		return 0, fmt.Errorf("invalid maximum number of redirects: %q", limit)
+	}
+
+	return maxRedirects, nil
+}
+
+// buildTLSOptions creates the options of a TLS checker.
+func buildTLSOptions(parentName string, group *propertyGroup) ([]checker.Option, error) {
+	var opts []checker.Option
+
+	if timeout, err := group.GetDuration("timeout"); err == nil {
+		opts = append(opts, checker.WithTLSTimeout(timeout))
+	}
+
+	if serverName, err := group.GetString("server-name"); err == nil && serverName != "" {
+		opts = append(opts, checker.WithTLSServerName(serverName))
+	}
+
+	if caFile, err := group.GetString("ca-file"); err == nil && caFile != "" {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid \"--%s.%s.ca-file\": %w", parentName, group.Name, err)
+		}
+		opts = append(opts, checker.WithTLSRootCAs(pool))
+	}
+
+	if minValidity, err := group.GetDuration("min-validity"); err == nil && minValidity != 0 {
+		if minValidity < 0 {
+			return nil, fmt.Errorf("invalid \"--%s.%s.min-validity\": must not be negative", parentName, group.Name)
+		}
+		opts = append(opts, checker.WithTLSMinValidity(minValidity))
+	}
+
+	if san, err := group.GetString("expected-san"); err == nil && san != "" {
+		opts = append(opts, checker.WithTLSExpectedSAN(san))
+	}
+
+	if fingerprint, err := group.GetString("expected-fingerprint"); err == nil && fingerprint != "" {
+		decoded, err := parseFingerprint(fingerprint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid \"--%s.%s.expected-fingerprint\": %w", parentName, group.Name, err)
+		}
+		opts = append(opts, checker.WithTLSExpectedFingerprint(decoded))
+	}
+
+	return opts, nil
+}
+
+// buildFileOptions creates the options of a file checker.
+func buildFileOptions(parentName string, group *propertyGroup) ([]checker.Option, error) {
+	var opts []checker.Option
+
+	if nonEmpty, err := group.GetBool("non-empty"); err == nil {
+		opts = append(opts, checker.WithFileNonEmpty(nonEmpty))
+	}
+
+	if pattern, err := group.GetString("content-regex"); err == nil && pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid \"--%s.%s.content-regex\": %w", parentName, group.Name, err)
+		}
+		opts = append(opts, checker.WithFileContentPattern(re))
+	}
+
+	jsonValue, _ := group.GetString("json-value") // Type is checked when parsing
+	if jsonPath, err := group.GetString("json-path"); err == nil && jsonPath != "" {
+		var expected *string
+		if jsonValue != "" {
+			expected = &jsonValue
+		}
+		opts = append(opts, checker.WithFileJSONPath(jsonPath, expected))
+	} else if jsonValue != "" {
+		return nil, fmt.Errorf("invalid \"--%s.%s.json-value\": requires \"--%s.%s.json-path\"", parentName, group.Name, parentName, group.Name)
+	}
+
+	if permissions, err := group.GetString("permissions"); err == nil && permissions != "" {
+		perm, err := strconv.ParseUint(permissions, 8, 32)
+		if err != nil || perm > 0o777 {
+			return nil, fmt.Errorf("invalid \"--%s.%s.permissions\": must be an octal mode like 0600: %q", parentName, group.Name, permissions)
+		}
+		opts = append(opts, checker.WithFilePermissions(fs.FileMode(perm)))
+	}
+
+	if modifiedAfterStart, err := group.GetBool("modified-after-start"); err == nil && modifiedAfterStart {
+		opts = append(opts, checker.WithFileModifiedAfter(time.Now()))
+	}
+
+	return opts, nil
+}
+
+// buildExecOptions creates the options of an exec checker.
+func buildExecOptions(parentName string, group *propertyGroup) ([]checker.Option, error) {
+	var opts []checker.Option
+
+	if args, err := group.GetStringSlices("arg"); err == nil {
+		opts = append(opts, checker.WithExecArgs(args))
+	}
+
+	if env, err := group.GetStringSlices("env"); err == nil {
+		resolvedEnv := make([]string, 0, len(env))
+		for _, entry := range env {
+			key, value, ok := strings.Cut(entry, "=")
+			if !ok || key == "" {
+				return nil, fmt.Errorf("invalid \"--%s.%s.env\": must be in KEY=VALUE format: %q", parentName, group.Name, entry)
+			}
+			resolvedValue, err := resolveSecret(value, false)
+			if err != nil {
+				return nil, fmt.Errorf("invalid \"--%s.%s.env\": failed to resolve variable: %w", parentName, group.Name, err)
+			}
+			resolvedEnv = append(resolvedEnv, key+"="+resolvedValue)
+		}
+		opts = append(opts, checker.WithExecEnv(resolvedEnv))
+	}
+
+	if timeout, err := group.GetDuration("timeout"); err == nil {
+		opts = append(opts, checker.WithExecTimeout(timeout))
+	}
+
+	if exitCodes, err := group.GetString("exit-codes"); err == nil {
+		codes, err := parseExitCodes(exitCodes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid \"--%s.%s.exit-codes\": %w", parentName, group.Name, err)
+		}
+		opts = append(opts, checker.WithExecExitCodes(codes))
+	}
+
+	expectOpt, err := buildExpectOption(parentName, group, checker.WithExecExpect, checker.WithExecExpectPattern)
+	if err != nil {
+		return nil, err
+	}
+	if expectOpt != nil {
+		opts = append(opts, expectOpt)
+	}
+
+	return opts, nil
+}
+
+// parseExitCodes parses a comma-separated list of exit codes and ranges (e.g. "0,3" or "0-2").
+func parseExitCodes(value string) ([]int, error) {
+	var codes []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+
+		first, last, isRange := strings.Cut(part, "-")
+		start, err := parseExitCode(first)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exit code %q: %w", part, err)
+		}
+		end := start
+		if isRange {
+			if end, err = parseExitCode(last); err != nil {
+				return nil, fmt.Errorf("invalid exit code range %q: %w", part, err)
+			}
+			if end < start {
+				return nil, fmt.Errorf("invalid exit code range %q: start is greater than end", part)
+			}
+		}
+
+		for code := start; code <= end; code++ {
+			codes = append(codes, code)
+		}
+	}
+
+	return codes, nil
+}
+
+// parseExitCode parses a single exit code, which the operating system limits to 0-255.
+func parseExitCode(value string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || code < 0 || code > 255 {
+		return 0, fmt.Errorf("must be a number between 0 and 255")
+	}
+	return code, nil
+}
+
+// buildWebSocketOptions creates the options of a WebSocket checker. Headers and TLS verification are
+// configured like for HTTP checks.
+func buildWebSocketOptions(parentName string, group *propertyGroup) ([]checker.Option, error) {
+	var opts []checker.Option
+
+	allowDuplicateHeaders, _ := group.GetBool("allow-duplicate-headers") // Type is checked when parsing
+	if headers, err := group.GetStringSlices("header"); err == nil {
+		headersMap, err := createHTTPHeadersMap(headers, allowDuplicateHeaders)
+		if err != nil {
+			return nil, fmt.Errorf("invalid \"--%s.%s.header\": %w", parentName, group.Name, err)
+		}
+		opts = append(opts, checker.WithWebSocketHeaders(headersMap))
+	}
+
+	if skipTLS, err := group.GetBool("skip-tls-verify"); err == nil {
+		opts = append(opts, checker.WithWebSocketSkipTLSVerify(skipTLS))
+	}
+
+	if timeout, err := group.GetDuration("timeout"); err == nil {
+		opts = append(opts, checker.WithWebSocketTimeout(timeout))
+	}
+
+	if send, err := group.GetString("send"); err == nil && send != "" {
+		message, err := unescape(send)
+		if err != nil {
+			return nil, fmt.Errorf("invalid \"--%s.%s.send\": %w", parentName, group.Name, err)
+		}
+		opts = append(opts, checker.WithWebSocketSend([]byte(message)))
+	}
+
+	expectOpt, err := buildExpectOption(parentName, group, checker.WithWebSocketExpect, checker.WithWebSocketExpectPattern)
+	if err != nil {
+		return nil, err
+	}
+	if expectOpt != nil {
+		opts = append(opts, expectOpt)
+	}
+
+	return opts, nil
+}
+
+// buildMongoDBOptions creates the options of a MongoDB checker.
+func buildMongoDBOptions(parentName string, group *propertyGroup) ([]checker.Option, error) {
+	var opts []checker.Option
+
+	if timeout, err := group.GetDuration("timeout"); err == nil {
+		opts = append(opts, checker.WithMongoDBTimeout(timeout))
+	}
+
+	if role, err := group.GetString("role"); err == nil {
+		parsedRole, err := parseMongoDBRole(role)
+		if err != nil {
+			return nil, fmt.Errorf("invalid \"--%s.%s.role\": %w", parentName, group.Name, err)
+		}
+		opts = append(opts, checker.WithMongoDBRole(parsedRole))
+	}
+
+	if replicaSet, err := group.GetString("replica-set"); err == nil && replicaSet != "" {
+		opts = append(opts, checker.WithMongoDBReplicaSet(replicaSet))
+	}
+
+	user, _ := group.GetString("username")
+	password, _ := group.GetString("password")
+	authSource, _ := group.GetString("auth-source")
+	mechanism, _ := group.GetString("auth-mechanism")
+
+	if user != "" || password != "" {
+		resolvedUser, resolvedPassword, err := resolveCredentials(parentName, group, user, password)
+		if err != nil {
+			return nil, err
+		}
+		if mechanism != checker.MongoDBAuthSCRAMSHA256 && mechanism != checker.MongoDBAuthSCRAMSHA1 {
+			return nil, fmt.Errorf("invalid \"--%s.%s.auth-mechanism\": must be %s or %s: %q",
+				parentName, group.Name, checker.MongoDBAuthSCRAMSHA256, checker.MongoDBAuthSCRAMSHA1, mechanism)
+		}
+		opts = append(opts, checker.WithMongoDBAuth(resolvedUser, resolvedPassword, authSource, mechanism))
+	}
+
+	tlsOpt, err := buildClientTLSOption(parentName, group, checker.WithMongoDBTLS)
+	if err != nil {
+		return nil, err
+	}
+	if tlsOpt != nil {
+		opts = append(opts, tlsOpt)
+	}
+
+	return opts, nil
+}
+
+// buildKafkaOptions creates the options of a Kafka checker.
+func buildKafkaOptions(parentName string, group *propertyGroup) ([]checker.Option, error) {
+	var opts []checker.Option
+
+	if timeout, err := group.GetDuration("timeout"); err == nil {
+		opts = append(opts, checker.WithKafkaTimeout(timeout))
+	}
+
+	if topic, err := group.GetString("topic"); err == nil && topic != "" {
+		opts = append(opts, checker.WithKafkaTopic(topic))
+	}
+
+	user, _ := group.GetString("username")
+	password, _ := group.GetString("password")
+	if user != "" || password != "" {
+		resolvedUser, resolvedPassword, err := resolveCredentials(parentName, group, user, password)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, checker.WithKafkaSASLPlain(resolvedUser, resolvedPassword))
+	}
+
+	tlsOpt, err := buildClientTLSOption(parentName, group, checker.WithKafkaTLS)
+	if err != nil {
+		return nil, err
+	}
+	if tlsOpt != nil {
+		opts = append(opts, tlsOpt)
+	}
+
+	return opts, nil
+}
+
+// buildAMQPOptions creates the options of an AMQP checker.
+func buildAMQPOptions(parentName string, group *propertyGroup) ([]checker.Option, error) {
+	var opts []checker.Option
+
+	if timeout, err := group.GetDuration("timeout"); err == nil {
+		opts = append(opts, checker.WithAMQPTimeout(timeout))
+	}
+
+	user, _ := group.GetString("username")
+	password, _ := group.GetString("password")
+	if user != "" || password != "" {
+		resolvedUser, resolvedPassword, err := resolveCredentials(parentName, group, user, password)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, checker.WithAMQPCredentials(resolvedUser, resolvedPassword))
+	}
+
+	// Names are encoded as AMQP short strings
+	if vhost, err := group.GetString("vhost"); err == nil && vhost != "" {
+		if len(vhost) > 255 {
+			return nil, fmt.Errorf("invalid \"--%s.%s.vhost\": must be at most 255 bytes", parentName, group.Name)
+		}
+		opts = append(opts, checker.WithAMQPVHost(vhost))
+	}
+
+	if queue, err := group.GetString("queue"); err == nil && queue != "" {
+		if len(queue) > 255 {
+			return nil, fmt.Errorf("invalid \"--%s.%s.queue\": must be at most 255 bytes", parentName, group.Name)
+		}
+		opts = append(opts, checker.WithAMQPQueue(queue))
+	}
+
+	tlsOpt, err := buildClientTLSOption(parentName, group, checker.WithAMQPTLS)
+	if err != nil {
+		return nil, err
+	}
+	if tlsOpt != nil {
+		opts = append(opts, tlsOpt)
+	}
+
+	return opts, nil
+}
+
+// buildMQTTOptions creates the options of an MQTT checker.
+func buildMQTTOptions(parentName string, group *propertyGroup, address string) ([]checker.Option, error) {
+	var opts []checker.Option
+
+	if timeout, err := group.GetDuration("timeout"); err == nil {
+		opts = append(opts, checker.WithMQTTTimeout(timeout))
+	}
+
+	if version, err := group.GetString("protocol-version"); err == nil {
+		parsedVersion, err := parseMQTTVersion(version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid \"--%s.%s.protocol-version\": %w", parentName, group.Name, err)
+		}
+		opts = append(opts, checker.WithMQTTVersion(parsedVersion))
+	}
+
+	if clientID, err := group.GetString("client-id"); err == nil && clientID != "" {
+		opts = append(opts, checker.WithMQTTClientID(clientID))
+	}
+
+	user, _ := group.GetString("username")
+	password, _ := group.GetString("password")
+	if user != "" || password != "" {
+		resolvedUser, resolvedPassword, err := resolveCredentials(parentName, group, user, password)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, checker.WithMQTTCredentials(resolvedUser, resolvedPassword))
+	}
+
+	// TLS is selected by the mqtts scheme instead of a "tls" property
+	isTLS := strings.HasPrefix(strings.ToLower(address), "mqtts://")
+	tlsConfig, err := buildClientTLSConfig(parentName, group, isTLS, "an mqtts:// address")
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		opts = append(opts, checker.WithMQTTTLS(tlsConfig))
+	}
+
+	// The topic is used for both subscribing and publishing, which does not allow wildcards
+	if topic, err := group.GetString("topic"); err == nil && topic != "" {
+		if strings.ContainsAny(topic, "+#") {
+			return nil, fmt.Errorf("invalid \"--%s.%s.topic\": must not contain wildcards: %q", parentName, group.Name, topic)
+		}
+		opts = append(opts, checker.WithMQTTTopic(topic))
+	}
+
+	return opts, nil
+}
+
+// parseMQTTVersion parses an MQTT protocol version into its protocol level.
+func parseMQTTVersion(value string) (byte, error) {
+	switch strings.TrimSpace(value) {
+	case "", "3.1.1":
+		return checker.MQTTVersion311, nil
+	case "5", "5.0":
+		return checker.MQTTVersion5, nil
+	default:
+		return 0, fmt.Errorf("must be 3.1.1 or 5: %q", value)
+	}
+}
+
+// buildNATSOptions creates the options of a NATS checker.
+func buildNATSOptions(parentName string, group *propertyGroup) ([]checker.Option, error) {
+	var opts []checker.Option
+
+	if timeout, err := group.GetDuration("timeout"); err == nil {
+		opts = append(opts, checker.WithNATSTimeout(timeout))
+	}
+
+	user, _ := group.GetString("username")
+	password, _ := group.GetString("password")
+	token, _ := group.GetString("token")
+	if token != "" {
+		if user != "" || password != "" {
+			return nil, fmt.Errorf("invalid \"--%s.%s.token\": cannot be combined with username and password", parentName, group.Name)
+		}
+		resolvedToken, err := resolveSecret(token, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid \"--%s.%s.token\": failed to resolve variable: %w", parentName, group.Name, err)
+		}
+		opts = append(opts, checker.WithNATSToken(resolvedToken))
+	}
+	if user != "" || password != "" {
+		resolvedUser, resolvedPassword, err := resolveCredentials(parentName, group, user, password)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, checker.WithNATSCredentials(resolvedUser, resolvedPassword))
+	}
+
+	if jetStream, err := group.GetBool("jetstream"); err == nil {
+		opts = append(opts, checker.WithNATSJetStream(jetStream))
+	}
+
+	// The stream name becomes a token of the JetStream API subject
+	if stream, err := group.GetString("stream"); err == nil && stream != "" {
+		if strings.ContainsAny(stream, ".*> \t\r\n") {
+			return nil, fmt.Errorf("invalid \"--%s.%s.stream\": must not contain whitespace, \".\", \"*\" or \">\": %q", parentName, group.Name, stream)
+		}
+		opts = append(opts, checker.WithNATSStream(stream))
+	}
+
+	tlsOpt, err := buildClientTLSOption(parentName, group, checker.WithNATSTLS)
+	if err != nil {
+		return nil, err
+	}
+	if tlsOpt != nil {
+		opts = append(opts, tlsOpt)
+	}
+
+	return opts, nil
+}
+
+// resolveCredentials resolves the values of the "username" and "password" properties. The username is required
+// when a password is set.
+func resolveCredentials(parentName string, group *propertyGroup, user, password string) (string, string, error) {
+	resolvedUser, err := resolveSecret(user, false)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid \"--%s.%s.username\": failed to resolve variable: %w", parentName, group.Name, err)
+	}
+	resolvedPassword, err := resolveSecret(password, true)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid \"--%s.%s.password\": failed to resolve variable: %w", parentName, group.Name, err)
+	}
+	if resolvedUser == "" {
+		return "", "", fmt.Errorf("invalid \"--%s.%s.username\": username is required when a password is set", parentName, group.Name)
+	}
+	return resolvedUser, resolvedPassword, nil
+}
+
+// parseMongoDBRole parses the required member state of a MongoDB node.
+func parseMongoDBRole(value string) (checker.MongoDBRole, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "any":
+		return checker.MongoDBAnyRole, nil
+	case "primary":
+		return checker.MongoDBPrimary, nil
+	case "secondary":
+		return checker.MongoDBSecondary, nil
+	default:
+		return "", fmt.Errorf("must be any, primary or secondary: %q", value)
+	}
+}
+
+// buildClientTLSOption creates the TLS client configuration option from the "tls", "skip-tls-verify" and "ca-file"
+// properties, or returns nil if TLS is disabled.
+func buildClientTLSOption(parentName string, group *propertyGroup, withTLS func(*tls.Config) checker.Option) (checker.Option, error) {
+	enabled, _ := group.GetBool("tls")
+
+	config, err := buildClientTLSConfig(parentName, group, enabled, fmt.Sprintf("\"--%s.%s.tls\"", parentName, group.Name))
+	if err != nil || config == nil {
+		return nil, err
+	}
+
+	return withTLS(config), nil
+}
+
+// buildClientTLSConfig creates a TLS client configuration from the "skip-tls-verify" and "ca-file" properties. If TLS
+// is disabled, it returns nil and rejects both properties, naming the requirement that enables TLS.
+func buildClientTLSConfig(parentName string, group *propertyGroup, enabled bool, requirement string) (*tls.Config, error) {
+	skipVerify, _ := group.GetBool("skip-tls-verify")
+	caFile, _ := group.GetString("ca-file")
+
+	if !enabled {
+		if skipVerify {
+			return nil, fmt.Errorf("invalid \"--%s.%s.skip-tls-verify\": requires %s", parentName, group.Name, requirement)
+		}
+		if caFile != "" {
+			return nil, fmt.Errorf("invalid \"--%s.%s.ca-file\": requires %s", parentName, group.Name, requirement)
+		}
+		return nil, nil
+	}
+
+	config := &tls.Config{InsecureSkipVerify: skipVerify}
+	if caFile != "" {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid \"--%s.%s.ca-file\": %w", parentName, group.Name, err)
+		}
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
+
+// loadCertPool reads PEM encoded CA certificates from path.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM encoded certificates found in %q", path)
+	}
+
+	return pool, nil
+}
+
+// parseFingerprint decodes a hex encoded SHA-256 fingerprint, optionally separated by colons.
+func parseFingerprint(value string) ([]byte, error) {
+	decoded, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(value), ":", ""))
+	if err != nil || len(decoded) != sha256.Size {
+		return nil, fmt.Errorf("must be a hex encoded SHA-256 fingerprint: %q", value)
+	}
+	return decoded, nil
+}
+
+// buildExpectOption creates the option for the expected response in "expect", or returns nil if it is not set.
+// With "expect-regex" the value is compiled as a regular expression, otherwise it is a literal with escape
+// sequences like \r\n.
+func buildExpectOption(
+	parentName string,
+	group *propertyGroup,
+	withExpect func([]byte) checker.Option,
+	withExpectPattern func(*regexp.Regexp) checker.Option,
+) (checker.Option, error) {
+	expect, _ := group.GetString("expect")
+	isRegex, _ := group.GetBool("expect-regex") // Type is checked when parsing
+
+	if expect == "" {
+		if isRegex {
+			return nil, fmt.Errorf("invalid \"--%s.%s.expect-regex\": requires \"--%s.%s.expect\"", parentName, group.Name, parentName, group.Name)
+		}
+		return nil, nil
+	}
+
+	if isRegex {
+		re, err := regexp.Compile(expect)
+		if err != nil {
+			return nil, fmt.Errorf("invalid \"--%s.%s.expect\": invalid pattern: %w", parentName, group.Name, err)
+		}
+		return withExpectPattern(re), nil
+	}
+
+	data, err := unescape(expect)
+	if err != nil {
+		return nil, fmt.Errorf("invalid \"--%s.%s.expect\": %w", parentName, group.Name, err)
+	}
+	return withExpect([]byte(data)), nil
+}
+
+// unescape replaces Go escape sequences like \r, \n, \t, \\ and \x00 in value.
+func unescape(value string) (string, error) {
+	var sb strings.Builder
+	for rest := value; rest != ""; {
+		r, multibyte, tail, err := strconv.UnquoteChar(rest, 0)
+		if err != nil {
+			return "", fmt.Errorf("invalid escape sequence in %q", value)
+		}
+		if multibyte {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteByte(byte(r))
+		}
+		rest = tail
+	}
+	return sb.String(), nil
+}
+
+// parseSocketType reports whether a unix socket type of "stream" or "datagram" is a datagram socket.
+func parseSocketType(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "stream":
+		return false, nil
+	case "datagram":
+		return true, nil
+	default:
+		return false, fmt.Errorf("must be stream or datagram: %q", value)
+	}
+}
+
+// parsePrivileged parses the ICMP socket mode "auto", "true" or "false". It returns no option for "auto",
+// leaving the checker to detect whether raw sockets are permitted.
+func parsePrivileged(value string) (checker.Option, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "auto":
+		return nil, nil
+	case "true":
+		return checker.WithICMPPrivileged(true), nil
+	case "false":
+		return checker.WithICMPPrivileged(false), nil
+	}
+
+	return nil, fmt.Errorf("must be auto, true or false: %q", value)
+}
+
 // createHTTPHeadersMap creates a map or slice-based map of HTTP headers from a slice of strings.
 // If allowDuplicateHeaders is true, headers with the same key will be overwritten.
 func createHTTPHeadersMap(headers []string, allowDuplicateHeaders bool) (map[string]string, error) {
@@ -135,7 +1198,7 @@ func createHTTPHeadersMap(headers []string, allowDuplicateHeaders bool) (map[str
 		key := strings.TrimSpace(parts[0])
 		value := strings.TrimSpace(parts[1])
 
-		resolved, err := resolver.ResolveVariable(value)
+		resolved, err := resolveSecret(value, redact.IsSensitiveHeader(key))
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve variable in header: %w", err)
 		}
@@ -149,3 +1212,18 @@ func createHTTPHeadersMap(headers []string, allowDuplicateHeaders bool) (map[str
 
 	return headersMap, nil
 }
+
+// resolveSecret resolves a variable and registers the result as sensitive if it was resolved from an
+// external source (e.g. "env:" or "file:") or if sensitive is true.
+func resolveSecret(value string, sensitive bool) (string, error) {
+	resolved, err := resolver.ResolveVariable(value)
+	if err != nil {
+		return "", err
+	}
+
+	if sensitive || resolved != value {
+		redact.Register(resolved)
+	}
+
+	return resolved, nil
+}
@@ -1,11 +1,20 @@
 package factory_test
 
 import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/containeroo/dynflags"
+	"github.com/containeroo/portpatrol/internal/config"
 	"github.com/containeroo/portpatrol/internal/factory"
+	"github.com/containeroo/portpatrol/internal/redact"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -42,6 +51,245 @@ func TestBuildCheckers(t *testing.T) {
 		assert.Equal(t, 5*time.Second, checkers[0].Interval)
 	})
 
+	t.Run("HTTP Checker With Body", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		httpGroup := df.Group("http")
+		httpGroup.String("address", "http://example.com", "HTTP target address")
+		httpGroup.String("body", "", "HTTP body")
+		httpGroup.String("content-type", "", "HTTP content type")
+
+		args := []string{
+			"--http.mygroup.address=http://example.com",
+			`--http.mygroup.body={"ping":true}`,
+			"--http.mygroup.content-type=application/json",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+	})
+
+	t.Run("HTTP Checker With Content Type Without Body", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		httpGroup := df.Group("http")
+		httpGroup.String("address", "http://example.com", "HTTP target address")
+		httpGroup.String("body", "", "HTTP body")
+		httpGroup.String("content-type", "", "HTTP content type")
+
+		args := []string{
+			"--http.mygroup.address=http://example.com",
+			"--http.mygroup.content-type=application/json",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.Nil(t, checkers)
+		assert.EqualError(t, err, "invalid \"--http.mygroup.content-type\": requires \"--http.mygroup.body\"")
+	})
+
+	t.Run("HTTP Checker With Unresolvable Body", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		httpGroup := df.Group("http")
+		httpGroup.String("address", "http://example.com", "HTTP target address")
+		httpGroup.String("body", "", "HTTP body")
+
+		args := []string{
+			"--http.mygroup.address=http://example.com",
+			"--http.mygroup.body=env:PORTPATROL_FACTORY_TEST_UNSET",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.Nil(t, checkers)
+		assert.ErrorContains(t, err, "invalid \"--http.mygroup.body\": failed to resolve variable")
+	})
+
+	t.Run("HTTP Checker With OAuth2", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		httpGroup := df.Group("http")
+		httpGroup.String("address", "http://example.com", "HTTP target address")
+		httpGroup.String("oauth2-token-url", "", "Token URL")
+		httpGroup.String("oauth2-client-id", "", "Client ID")
+		httpGroup.String("oauth2-client-secret", "", "Client secret")
+		httpGroup.String("oauth2-scopes", "", "Scopes")
+
+		args := []string{
+			"--http.mygroup.address=http://example.com",
+			"--http.mygroup.oauth2-token-url=http://auth.example.com/token",
+			"--http.mygroup.oauth2-client-id=portpatrol",
+			"--http.mygroup.oauth2-client-secret=factory-test-client-secret",
+			"--http.mygroup.oauth2-scopes=read, health",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+		assert.Equal(t, "xxxxx", redact.String("factory-test-client-secret"))
+	})
+
+	t.Run("HTTP Checker With OAuth2 Missing Token URL", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		httpGroup := df.Group("http")
+		httpGroup.String("address", "http://example.com", "HTTP target address")
+		httpGroup.String("oauth2-token-url", "", "Token URL")
+		httpGroup.String("oauth2-client-id", "", "Client ID")
+
+		args := []string{
+			"--http.mygroup.address=http://example.com",
+			"--http.mygroup.oauth2-client-id=portpatrol",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.Nil(t, checkers)
+		assert.EqualError(t, err, "missing \"--http.mygroup.oauth2-token-url\": required for OAuth2 client credentials")
+	})
+
+	t.Run("HTTP Checker With OAuth2 Skip TLS Verify Without OAuth2", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		httpGroup := df.Group("http")
+		httpGroup.String("address", "http://example.com", "HTTP target address")
+		httpGroup.String("oauth2-token-url", "", "Token URL")
+		httpGroup.Bool("oauth2-skip-tls-verify", false, "Skip TLS verification of the token endpoint")
+
+		args := []string{
+			"--http.mygroup.address=https://example.com",
+			"--http.mygroup.oauth2-skip-tls-verify=true",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.Nil(t, checkers)
+		assert.EqualError(t, err, "invalid \"--http.mygroup.oauth2-skip-tls-verify\": requires \"--http.mygroup.oauth2-token-url\"")
+	})
+
+	t.Run("HTTP Checker With Basic Auth And OAuth2", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		httpGroup := df.Group("http")
+		httpGroup.String("address", "http://example.com", "HTTP target address")
+		httpGroup.String("basic-auth-user", "", "User")
+		httpGroup.String("basic-auth-password", "", "Password")
+		httpGroup.String("oauth2-token-url", "", "Token URL")
+
+		args := []string{
+			"--http.mygroup.address=http://example.com",
+			"--http.mygroup.basic-auth-user=admin",
+			"--http.mygroup.basic-auth-password=factory-test-password",
+			"--http.mygroup.oauth2-token-url=http://auth.example.com/token",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.Nil(t, checkers)
+		assert.EqualError(t, err, "invalid \"--http.mygroup.oauth2-token-url\": OAuth2 cannot be combined with basic authentication")
+	})
+
+	t.Run("HTTP Checker With Redirect Limit", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		httpGroup := df.Group("http")
+		httpGroup.String("address", "http://example.com", "HTTP target address")
+		httpGroup.String("follow-redirects", "true", "Follow redirects")
+		httpGroup.Duration("max-response-time", 0, "Max response time")
+
+		args := []string{
+			"--http.mygroup.address=http://example.com",
+			"--http.mygroup.follow-redirects=max=3",
+			"--http.mygroup.max-response-time=2s",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+	})
+
+	t.Run("HTTP Checker With Invalid Redirect Policy", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		httpGroup := df.Group("http")
+		httpGroup.String("address", "http://example.com", "HTTP target address")
+		httpGroup.String("follow-redirects", "true", "Follow redirects")
+
+		args := []string{
+			"--http.mygroup.address=http://example.com",
+			"--http.mygroup.follow-redirects=max=-1",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.Nil(t, checkers)
+		assert.EqualError(t, err, "invalid \"--http.mygroup.follow-redirects\": invalid maximum number of redirects: \"-1\"")
+	})
+
+	t.Run("HTTP Checker With Invalid Expected Header", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		httpGroup := df.Group("http")
+		httpGroup.String("address", "http://example.com", "HTTP target address")
+		httpGroup.StringSlices("expected-header", nil, "Expected headers")
+
+		args := []string{
+			"--http.mygroup.address=http://example.com",
+			"--http.mygroup.expected-header=X-App-Version=^1.2.3$",
+			"--http.mygroup.expected-header=X-Ready",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.Nil(t, checkers)
+		assert.EqualError(t, err, "invalid \"--http.mygroup.expected-header\": invalid expected header format: \"X-Ready\"")
+	})
+
+	t.Run("HTTP Checker With Invalid Expected Header Pattern", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		httpGroup := df.Group("http")
+		httpGroup.String("address", "http://example.com", "HTTP target address")
+		httpGroup.StringSlices("expected-header", nil, "Expected headers")
+
+		args := []string{
+			"--http.mygroup.address=http://example.com",
+			"--http.mygroup.expected-header=X-App-Version=(1.2",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.Nil(t, checkers)
+		assert.ErrorContains(t, err, "invalid \"--http.mygroup.expected-header\": invalid pattern for header \"X-App-Version\"")
+	})
+
 	t.Run("Missing Address", func(t *testing.T) {
 		t.Parallel()
 
@@ -58,6 +306,42 @@ func TestBuildCheckers(t *testing.T) {
 		assert.ErrorContains(t, err, "missing address for http checker")
 	})
 
+	t.Run("Unresolvable Address", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		httpGroup := df.Group("http")
+		httpGroup.String("address", "", "HTTP target address")
+
+		args := []string{"--http.mygroup.address=env:PORTPATROL_FACTORY_TEST_UNSET"}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.Nil(t, checkers)
+		assert.ErrorContains(t, err, "invalid \"--http.mygroup.address\": failed to resolve variable")
+	})
+
+	t.Run("Sensitive Header Values Are Redacted", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		httpGroup := df.Group("http")
+		httpGroup.String("address", "http://example.com", "HTTP target address")
+		httpGroup.StringSlices("header", nil, "HTTP headers")
+
+		args := []string{
+			"--http.mygroup.address=http://example.com",
+			"--http.mygroup.header=X-Api-Key=factory-test-api-key",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, "key=xxxxx", redact.String("key=factory-test-api-key"))
+	})
+
 	t.Run("Invalid Check Type", func(t *testing.T) {
 		t.Parallel()
 
@@ -160,19 +444,283 @@ func TestBuildCheckers(t *testing.T) {
 		assert.Equal(t, "127.0.0.1:8080", checkers[0].Checker.Address())
 	})
 
-	t.Run("Valid ICMP Checker", func(t *testing.T) {
+	t.Run("TCP Checker Applies Timeout", func(t *testing.T) {
 		t.Parallel()
 
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer ln.Close()
+
 		df := dynflags.New(dynflags.ContinueOnError)
-		icmpGroup := df.Group("icmp")
-		icmpGroup.String("address", "8.8.8.8", "ICMP target address")
-		icmpGroup.Duration("read-timeout", 2*time.Second, "Read timeout")
-		icmpGroup.Duration("write-timeout", 2*time.Second, "Write timeout")
+		tcpGroup := df.Group("tcp")
+		tcpGroup.String("address", "", "TCP target address")
+		tcpGroup.Duration("timeout", 2*time.Second, "Timeout")
 
 		args := []string{
-			"--icmp.mygroup.address=8.8.8.8",
-			"--icmp.mygroup.read-timeout=2s",
-			"--icmp.mygroup.write-timeout=2s",
+			"--tcp.mygroup.address=" + ln.Addr().String(),
+			"--tcp.mygroup.timeout=1ns",
+		}
+		err = df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+
+		err = checkers[0].Checker.Check(context.Background())
+		assert.Error(t, err)
+		assert.ErrorContains(t, err, "i/o timeout")
+	})
+
+	t.Run("Unsupported Property", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		tcpGroup := df.Group("tcp")
+		tcpGroup.String("address", "", "TCP target address")
+		tcpGroup.String("method", "GET", "HTTP method")
+
+		args := []string{
+			"--tcp.mygroup.address=127.0.0.1:8080",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "invalid \"--tcp.mygroup.method\": not supported by tcp checkers")
+	})
+
+	t.Run("All Configured Properties Are Consumed", func(t *testing.T) {
+		t.Parallel()
+
+		args := []string{
+			"--http.web.address=http://127.0.0.1:8080",
+			"--icmp.host.address=127.0.0.1",
+			"--tcp.db.address=127.0.0.1:5432",
+			"--tls.api.address=127.0.0.1:8443",
+			"--udp.syslog.address=127.0.0.1:514",
+			"--unix.envoy.address=/var/run/envoy.sock",
+			"--file.secret.address=/vault/secrets/config",
+			"--exec.postgres.address=pg_isready",
+			"--websocket.gateway.address=ws://127.0.0.1:8080/ws",
+			"--mongodb.db.address=127.0.0.1:27017",
+			"--kafka.broker.address=127.0.0.1:9092",
+			"--amqp.rabbitmq.address=127.0.0.1:5672",
+			"--mqtt.mosquitto.address=mqtt://127.0.0.1:1883",
+			"--nats.cluster.address=127.0.0.1:4222",
+		}
+		var output strings.Builder
+		parsedFlags, err := config.ParseFlags(args, "1.0.0", &output)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(parsedFlags.DynFlags, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 14)
+	})
+
+	t.Run("TCP Checker With Resolve Override", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		tcpGroup := df.Group("tcp")
+		tcpGroup.String("address", "", "TCP target address")
+		tcpGroup.StringSlices("resolve", nil, "Resolve overrides")
+
+		args := []string{
+			"--tcp.mygroup.address=db.example.com:5432",
+			"--tcp.mygroup.resolve=db.example.com:5432:10.0.0.12",
+			"--tcp.mygroup.resolve=db.example.com:5433:[fd00::12]",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+	})
+
+	t.Run("TCP Checker With Send And Expect", func(t *testing.T) {
+		t.Parallel()
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer ln.Close()
+
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			if line == "PING\r\n" {
+				_, _ = conn.Write([]byte("+PONG\r\n"))
+			}
+		}()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		tcpGroup := df.Group("tcp")
+		tcpGroup.String("address", "", "TCP target address")
+		tcpGroup.String("send", "", "Probe")
+		tcpGroup.String("expect", "", "Expected response")
+		tcpGroup.Bool("expect-regex", false, "Expect is a pattern")
+
+		args := []string{
+			"--tcp.mygroup.address=" + ln.Addr().String(),
+			`--tcp.mygroup.send=PING\r\n`,
+			`--tcp.mygroup.expect=^\+PONG\r\n$`,
+			"--tcp.mygroup.expect-regex=true",
+		}
+		err = df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+		assert.NoError(t, checkers[0].Checker.Check(context.Background()))
+	})
+
+	t.Run("TCP Checker With Invalid Escape Sequence", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		tcpGroup := df.Group("tcp")
+		tcpGroup.String("address", "", "TCP target address")
+		tcpGroup.String("send", "", "Probe")
+
+		args := []string{
+			"--tcp.mygroup.address=localhost:11211",
+			`--tcp.mygroup.send=stats\q`,
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.EqualError(t, err, `invalid "--tcp.mygroup.send": invalid escape sequence in "stats\\q"`)
+	})
+
+	t.Run("TCP Checker With Invalid Expect Pattern", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		tcpGroup := df.Group("tcp")
+		tcpGroup.String("address", "", "TCP target address")
+		tcpGroup.String("expect", "", "Expected response")
+		tcpGroup.Bool("expect-regex", false, "Expect is a pattern")
+
+		args := []string{
+			"--tcp.mygroup.address=localhost:25",
+			"--tcp.mygroup.expect=^220 (",
+			"--tcp.mygroup.expect-regex=true",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid "--tcp.mygroup.expect": invalid pattern: `)
+	})
+
+	t.Run("Expect Regex Without Expect", func(t *testing.T) {
+		t.Parallel()
+
+		for _, checkType := range []string{"tcp", "udp", "exec", "websocket"} {
+			df := dynflags.New(dynflags.ContinueOnError)
+			group := df.Group(checkType)
+			group.String("address", "", "Target address")
+			group.String("expect", "", "Expected response")
+			group.Bool("expect-regex", false, "Expect is a pattern")
+
+			args := []string{
+				"--" + checkType + ".mygroup.address=localhost:25",
+				"--" + checkType + ".mygroup.expect-regex=true",
+			}
+			err := df.Parse(args)
+			assert.NoError(t, err)
+
+			_, err = factory.BuildCheckers(df, 2*time.Second)
+			assert.EqualError(t, err, fmt.Sprintf("invalid \"--%[1]s.mygroup.expect-regex\": requires \"--%[1]s.mygroup.expect\"", checkType))
+		}
+	})
+
+	t.Run("HTTP Checker With Invalid Resolve Override", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		httpGroup := df.Group("http")
+		httpGroup.String("address", "", "HTTP target address")
+		httpGroup.StringSlices("resolve", nil, "Resolve overrides")
+
+		args := []string{
+			"--http.mygroup.address=http://example.com",
+			"--http.mygroup.resolve=example.com:80:not-an-ip",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.Nil(t, checkers)
+		assert.EqualError(t, err, "invalid \"--http.mygroup.resolve\": invalid IP in resolve entry: \"example.com:80:not-an-ip\"")
+	})
+
+	t.Run("TCP Checker With Min Addresses Without All Addresses", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		tcpGroup := df.Group("tcp")
+		tcpGroup.String("address", "", "TCP target address")
+		tcpGroup.Bool("all-addresses", false, "All addresses")
+		tcpGroup.Int("min-addresses", 0, "Min addresses")
+
+		args := []string{
+			"--tcp.mygroup.address=db.example.com:5432",
+			"--tcp.mygroup.min-addresses=2",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.Nil(t, checkers)
+		assert.EqualError(t, err, "invalid \"--tcp.mygroup.min-addresses\": requires \"--tcp.mygroup.all-addresses\"")
+	})
+
+	t.Run("TCP Checker With All Addresses", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		tcpGroup := df.Group("tcp")
+		tcpGroup.String("address", "", "TCP target address")
+		tcpGroup.Bool("all-addresses", false, "All addresses")
+		tcpGroup.Int("min-addresses", 0, "Min addresses")
+
+		args := []string{
+			"--tcp.mygroup.address=db.example.com:5432",
+			"--tcp.mygroup.all-addresses=true",
+			"--tcp.mygroup.min-addresses=2",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+	})
+
+	t.Run("Valid ICMP Checker", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		icmpGroup := df.Group("icmp")
+		icmpGroup.String("address", "8.8.8.8", "ICMP target address")
+		icmpGroup.Duration("read-timeout", 2*time.Second, "Read timeout")
+		icmpGroup.Duration("write-timeout", 2*time.Second, "Write timeout")
+
+		args := []string{
+			"--icmp.mygroup.address=8.8.8.8",
+			"--icmp.mygroup.read-timeout=2s",
+			"--icmp.mygroup.write-timeout=2s",
 		}
 		err := df.Parse(args)
 		assert.NoError(t, err)
@@ -183,6 +731,1108 @@ func TestBuildCheckers(t *testing.T) {
 		assert.Equal(t, "8.8.8.8", checkers[0].Checker.Address())
 	})
 
+	t.Run("ICMP Checker Unprivileged", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		icmpGroup := df.Group("icmp")
+		icmpGroup.String("address", "", "ICMP target address")
+		icmpGroup.String("privileged", "auto", "Socket mode")
+
+		args := []string{
+			"--icmp.mygroup.address=127.0.0.1",
+			"--icmp.mygroup.privileged=false",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+	})
+
+	t.Run("ICMP Checker With Burst", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		icmpGroup := df.Group("icmp")
+		icmpGroup.String("address", "", "ICMP target address")
+		icmpGroup.Int("count", 1, "Echo requests")
+		icmpGroup.Int("max-loss", 0, "Maximum loss")
+		icmpGroup.Duration("max-rtt", 0, "Maximum round-trip time")
+		icmpGroup.Int("rtt-percentile", 0, "Percentile")
+
+		args := []string{
+			"--icmp.mygroup.address=127.0.0.1",
+			"--icmp.mygroup.count=10",
+			"--icmp.mygroup.max-loss=20",
+			"--icmp.mygroup.max-rtt=150ms",
+			"--icmp.mygroup.rtt-percentile=95",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+	})
+
+	t.Run("ICMP Checker With RTT Percentile Without Max RTT", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		icmpGroup := df.Group("icmp")
+		icmpGroup.String("address", "", "ICMP target address")
+		icmpGroup.Duration("max-rtt", 0, "Maximum round-trip time")
+		icmpGroup.Int("rtt-percentile", 0, "Percentile")
+
+		args := []string{
+			"--icmp.mygroup.address=127.0.0.1",
+			"--icmp.mygroup.rtt-percentile=95",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.EqualError(t, err, "invalid \"--icmp.mygroup.rtt-percentile\": requires \"--icmp.mygroup.max-rtt\"")
+	})
+
+	t.Run("ICMP Checker With Invalid Max Loss", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		icmpGroup := df.Group("icmp")
+		icmpGroup.String("address", "", "ICMP target address")
+		icmpGroup.Int("max-loss", 0, "Maximum loss")
+
+		args := []string{
+			"--icmp.mygroup.address=127.0.0.1",
+			"--icmp.mygroup.max-loss=120",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "invalid \"--icmp.mygroup.max-loss\": must be between 0 and 100")
+	})
+
+	t.Run("ICMP Checker With Invalid Count", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		icmpGroup := df.Group("icmp")
+		icmpGroup.String("address", "", "ICMP target address")
+		icmpGroup.Int("count", 1, "Echo requests")
+
+		args := []string{
+			"--icmp.mygroup.address=127.0.0.1",
+			"--icmp.mygroup.count=0",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "invalid \"--icmp.mygroup.count\": must be at least 1")
+	})
+
+	t.Run("ICMP Checker With Path MTU Options", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		icmpGroup := df.Group("icmp")
+		icmpGroup.String("address", "", "ICMP target address")
+		icmpGroup.Int("size", 0, "Payload size")
+		icmpGroup.Int("ttl", 0, "Time to live")
+		icmpGroup.Bool("dont-fragment", false, "Don't fragment")
+
+		args := []string{
+			"--icmp.mygroup.address=127.0.0.1",
+			"--icmp.mygroup.size=1400",
+			"--icmp.mygroup.ttl=64",
+			"--icmp.mygroup.dont-fragment=true",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+	})
+
+	t.Run("ICMP Checker With Invalid Size", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		icmpGroup := df.Group("icmp")
+		icmpGroup.String("address", "", "ICMP target address")
+		icmpGroup.Int("size", 0, "Payload size")
+
+		args := []string{
+			"--icmp.mygroup.address=127.0.0.1",
+			"--icmp.mygroup.size=70000",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "invalid \"--icmp.mygroup.size\": must be between 0 and 65507")
+	})
+
+	t.Run("ICMP Checker With Invalid TTL", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		icmpGroup := df.Group("icmp")
+		icmpGroup.String("address", "", "ICMP target address")
+		icmpGroup.Int("ttl", 0, "Time to live")
+
+		args := []string{
+			"--icmp.mygroup.address=127.0.0.1",
+			"--icmp.mygroup.ttl=256",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "invalid \"--icmp.mygroup.ttl\": must be between 0 and 255")
+	})
+
+	t.Run("ICMP Checker With Invalid Privileged Mode", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		icmpGroup := df.Group("icmp")
+		icmpGroup.String("address", "", "ICMP target address")
+		icmpGroup.String("privileged", "auto", "Socket mode")
+
+		args := []string{
+			"--icmp.mygroup.address=127.0.0.1",
+			"--icmp.mygroup.privileged=maybe",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "invalid \"--icmp.mygroup.privileged\": must be auto, true or false: \"maybe\"")
+	})
+
+	t.Run("Valid TLS Checker", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		tlsGroup := df.Group("tls")
+		tlsGroup.String("address", "", "TLS target address")
+		tlsGroup.Duration("timeout", 2*time.Second, "Timeout")
+		tlsGroup.String("server-name", "", "Server name")
+		tlsGroup.Duration("min-validity", 0, "Minimum validity")
+		tlsGroup.String("expected-san", "", "Expected SAN")
+		tlsGroup.String("expected-fingerprint", "", "Expected fingerprint")
+
+		args := []string{
+			"--tls.mygroup.address=example.com:443",
+			"--tls.mygroup.timeout=3s",
+			"--tls.mygroup.server-name=example.com",
+			"--tls.mygroup.min-validity=168h",
+			"--tls.mygroup.expected-san=www.example.com",
+			"--tls.mygroup.expected-fingerprint=" + strings.Repeat("AB:", 31) + "AB",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+		assert.Equal(t, "TLS", checkers[0].Checker.Type())
+		assert.Equal(t, "example.com:443", checkers[0].Checker.Address())
+	})
+
+	t.Run("Invalid TLS Fingerprint", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		tlsGroup := df.Group("tls")
+		tlsGroup.String("address", "", "TLS target address")
+		tlsGroup.String("expected-fingerprint", "", "Expected fingerprint")
+
+		args := []string{
+			"--tls.mygroup.address=example.com:443",
+			"--tls.mygroup.expected-fingerprint=AB:CD",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "invalid \"--tls.mygroup.expected-fingerprint\": must be a hex encoded SHA-256 fingerprint: \"AB:CD\"")
+	})
+
+	t.Run("Invalid TLS CA File", func(t *testing.T) {
+		t.Parallel()
+
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		assert.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		tlsGroup := df.Group("tls")
+		tlsGroup.String("address", "", "TLS target address")
+		tlsGroup.String("ca-file", "", "CA file")
+
+		args := []string{
+			"--tls.mygroup.address=example.com:443",
+			"--tls.mygroup.ca-file=" + caFile,
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.EqualError(t, err, fmt.Sprintf("invalid \"--tls.mygroup.ca-file\": no PEM encoded certificates found in %q", caFile))
+	})
+
+	t.Run("Valid UDP Checker", func(t *testing.T) {
+		t.Parallel()
+
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		go func() {
+			buf := make([]byte, 512)
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil || string(buf[:n]) != "ping\n" {
+				return
+			}
+			_, _ = conn.WriteTo([]byte("PONG 1\n"), addr)
+		}()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		udpGroup := df.Group("udp")
+		udpGroup.String("address", "", "UDP target address")
+		udpGroup.Duration("timeout", 2*time.Second, "Timeout")
+		udpGroup.String("send", "", "Datagram to send")
+		udpGroup.String("expect", "", "Expected reply")
+		udpGroup.Bool("expect-regex", false, "Treat expect as a regular expression")
+
+		args := []string{
+			"--udp.mygroup.address=" + conn.LocalAddr().String(),
+			"--udp.mygroup.timeout=1s",
+			`--udp.mygroup.send=ping\n`,
+			`--udp.mygroup.expect=^PONG \d+`,
+			"--udp.mygroup.expect-regex=true",
+		}
+		err = df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+		assert.Equal(t, "UDP", checkers[0].Checker.Type())
+
+		err = checkers[0].Checker.Check(context.Background())
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid UDP Expect Pattern", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		udpGroup := df.Group("udp")
+		udpGroup.String("address", "", "UDP target address")
+		udpGroup.String("expect", "", "Expected reply")
+		udpGroup.Bool("expect-regex", false, "Treat expect as a regular expression")
+
+		args := []string{
+			"--udp.mygroup.address=127.0.0.1:514",
+			"--udp.mygroup.expect=(",
+			"--udp.mygroup.expect-regex=true",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid "--udp.mygroup.expect": invalid pattern: `)
+	})
+
+	t.Run("Valid Unix Checker", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "app.sock")
+		conn, err := net.ListenPacket("unixgram", path)
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		unixGroup := df.Group("unix")
+		unixGroup.String("address", "", "Socket path")
+		unixGroup.Duration("timeout", 1*time.Second, "Timeout")
+		unixGroup.String("socket-type", "stream", "Socket type")
+
+		args := []string{
+			"--unix.mygroup.address=unix://" + path,
+			"--unix.mygroup.socket-type=datagram",
+		}
+		err = df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+		assert.Equal(t, "UNIX", checkers[0].Checker.Type())
+
+		err = checkers[0].Checker.Check(context.Background())
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid Unix Socket Type", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		unixGroup := df.Group("unix")
+		unixGroup.String("address", "", "Socket path")
+		unixGroup.String("socket-type", "stream", "Socket type")
+
+		args := []string{
+			"--unix.mygroup.address=/var/run/app.sock",
+			"--unix.mygroup.socket-type=seqpacket",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "invalid \"--unix.mygroup.socket-type\": must be stream or datagram: \"seqpacket\"")
+	})
+
+	t.Run("Valid File Checker", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "status.json")
+		assert.NoError(t, os.WriteFile(path, []byte(`{"ready":true}`), 0o600))
+		assert.NoError(t, os.Chmod(path, 0o600))
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		fileGroup := df.Group("file")
+		fileGroup.String("address", "", "Path")
+		fileGroup.Bool("non-empty", false, "Non-empty")
+		fileGroup.String("content-regex", "", "Content pattern")
+		fileGroup.String("json-path", "", "JSON path")
+		fileGroup.String("json-value", "", "JSON value")
+		fileGroup.String("permissions", "", "Permissions")
+		fileGroup.Bool("modified-after-start", false, "Modified after start")
+
+		args := []string{
+			"--file.mygroup.address=" + path,
+			"--file.mygroup.non-empty=true",
+			"--file.mygroup.content-regex=ready",
+			"--file.mygroup.json-path=ready",
+			"--file.mygroup.json-value=true",
+			"--file.mygroup.permissions=0600",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+		assert.Equal(t, "FILE", checkers[0].Checker.Type())
+
+		err = checkers[0].Checker.Check(context.Background())
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid File Permissions", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		fileGroup := df.Group("file")
+		fileGroup.String("address", "", "Path")
+		fileGroup.String("permissions", "", "Permissions")
+
+		args := []string{
+			"--file.mygroup.address=/vault/secrets/config",
+			"--file.mygroup.permissions=rw-------",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "invalid \"--file.mygroup.permissions\": must be an octal mode like 0600: \"rw-------\"")
+	})
+
+	t.Run("File JSON Value Without Path", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		fileGroup := df.Group("file")
+		fileGroup.String("address", "", "Path")
+		fileGroup.String("json-path", "", "JSON path")
+		fileGroup.String("json-value", "", "JSON value")
+
+		args := []string{
+			"--file.mygroup.address=/vault/secrets/config",
+			"--file.mygroup.json-value=true",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "invalid \"--file.mygroup.json-value\": requires \"--file.mygroup.json-path\"")
+	})
+
+	t.Run("Valid Exec Checker", func(t *testing.T) {
+		t.Parallel()
+
+		secrets := filepath.Join(t.TempDir(), "db.env")
+		assert.NoError(t, os.WriteFile(secrets, []byte("HOST=db\n"), 0o600))
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		execGroup := df.Group("exec")
+		execGroup.String("address", "", "Command")
+		execGroup.StringSlices("arg", nil, "Argument")
+		execGroup.StringSlices("env", nil, "Environment variable")
+		execGroup.Duration("timeout", 5*time.Second, "Timeout")
+		execGroup.String("exit-codes", "0", "Exit codes")
+		execGroup.String("expect", "", "Expected output")
+		execGroup.Bool("expect-regex", false, "Treat expect as a regular expression")
+
+		args := []string{
+			"--exec.mygroup.address=sh",
+			"--exec.mygroup.arg=-c",
+			`--exec.mygroup.arg=echo "accepting connections on $PGHOST"; exit 3`,
+			"--exec.mygroup.env=PGHOST=file:" + secrets + "//HOST",
+			"--exec.mygroup.timeout=2s",
+			"--exec.mygroup.exit-codes=0,3",
+			"--exec.mygroup.expect=accepting connections on db",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+		assert.Equal(t, "EXEC", checkers[0].Checker.Type())
+
+		err = checkers[0].Checker.Check(context.Background())
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid Exec Env", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		execGroup := df.Group("exec")
+		execGroup.String("address", "", "Command")
+		execGroup.StringSlices("env", nil, "Environment variable")
+
+		args := []string{
+			"--exec.mygroup.address=pg_isready",
+			"--exec.mygroup.env=PGHOST",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "invalid \"--exec.mygroup.env\": must be in KEY=VALUE format: \"PGHOST\"")
+	})
+
+	t.Run("Invalid Exec Exit Codes", func(t *testing.T) {
+		t.Parallel()
+
+		for exitCodes, expected := range map[string]string{
+			"zero":  `invalid exit code "zero": must be a number between 0 and 255`,
+			"0,256": `invalid exit code "256": must be a number between 0 and 255`,
+			"0-x":   `invalid exit code range "0-x": must be a number between 0 and 255`,
+			"3-1":   `invalid exit code range "3-1": start is greater than end`,
+		} {
+			df := dynflags.New(dynflags.ContinueOnError)
+			execGroup := df.Group("exec")
+			execGroup.String("address", "", "Command")
+			execGroup.String("exit-codes", "0", "Exit codes")
+
+			args := []string{
+				"--exec.mygroup.address=pg_isready",
+				"--exec.mygroup.exit-codes=" + exitCodes,
+			}
+			err := df.Parse(args)
+			assert.NoError(t, err)
+
+			_, err = factory.BuildCheckers(df, 2*time.Second)
+			assert.EqualError(t, err, "invalid \"--exec.mygroup.exit-codes\": "+expected)
+		}
+	})
+
+	t.Run("Valid WebSocket Checker", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		wsGroup := df.Group("websocket")
+		wsGroup.String("address", "", "WebSocket target URL")
+		wsGroup.StringSlices("header", nil, "HTTP header")
+		wsGroup.Bool("skip-tls-verify", false, "Skip TLS verification")
+		wsGroup.Duration("timeout", 2*time.Second, "Timeout")
+		wsGroup.String("send", "", "Message")
+		wsGroup.String("expect", "", "Expected reply")
+		wsGroup.Bool("expect-regex", false, "Treat expect as a regular expression")
+
+		args := []string{
+			"--websocket.mygroup.address=wss://gateway.example.com/ws",
+			"--websocket.mygroup.header=Authorization=Bearer token",
+			"--websocket.mygroup.skip-tls-verify=true",
+			"--websocket.mygroup.timeout=3s",
+			`--websocket.mygroup.send={"type":"ping"}`,
+			`--websocket.mygroup.expect="type":"pong"`,
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+		assert.Equal(t, "WEBSOCKET", checkers[0].Checker.Type())
+		assert.Equal(t, "wss://gateway.example.com/ws", checkers[0].Checker.Address())
+	})
+
+	t.Run("Invalid WebSocket Header", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		wsGroup := df.Group("websocket")
+		wsGroup.String("address", "", "WebSocket target URL")
+		wsGroup.StringSlices("header", nil, "HTTP header")
+
+		args := []string{
+			"--websocket.mygroup.address=ws://gateway.example.com/ws",
+			"--websocket.mygroup.header=Authorization",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "invalid \"--websocket.mygroup.header\": invalid header format: \"Authorization\"")
+	})
+
+	t.Run("WebSocket Duplicate Headers", func(t *testing.T) {
+		t.Parallel()
+
+		for _, allow := range []bool{false, true} {
+			df := dynflags.New(dynflags.ContinueOnError)
+			wsGroup := df.Group("websocket")
+			wsGroup.String("address", "", "WebSocket target URL")
+			wsGroup.StringSlices("header", nil, "HTTP header")
+			wsGroup.Bool("allow-duplicate-headers", false, "Allow duplicate HTTP headers")
+
+			args := []string{
+				"--websocket.mygroup.address=ws://gateway.example.com/ws",
+				"--websocket.mygroup.header=Authorization=Bearer old",
+				"--websocket.mygroup.header=Authorization=Bearer new",
+				fmt.Sprintf("--websocket.mygroup.allow-duplicate-headers=%t", allow),
+			}
+			err := df.Parse(args)
+			assert.NoError(t, err)
+
+			_, err = factory.BuildCheckers(df, 2*time.Second)
+			if allow {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, "invalid \"--websocket.mygroup.header\": duplicate header: \"Authorization=Bearer new\"")
+			}
+		}
+	})
+
+	t.Run("Valid MongoDB Checker", func(t *testing.T) {
+		t.Parallel()
+
+		secrets := filepath.Join(t.TempDir(), "mongo.env")
+		assert.NoError(t, os.WriteFile(secrets, []byte("PASSWORD=secret\n"), 0o600))
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		mongoGroup := df.Group("mongodb")
+		mongoGroup.String("address", "", "MongoDB target address")
+		mongoGroup.Duration("timeout", 2*time.Second, "Timeout")
+		mongoGroup.String("role", "any", "Required member state")
+		mongoGroup.String("replica-set", "", "Replica set name")
+		mongoGroup.String("username", "", "Username")
+		mongoGroup.String("password", "", "Password")
+		mongoGroup.String("auth-source", "admin", "Database holding the user")
+		mongoGroup.String("auth-mechanism", "SCRAM-SHA-256", "Authentication mechanism")
+		mongoGroup.Bool("tls", false, "Connect with TLS")
+		mongoGroup.Bool("skip-tls-verify", false, "Skip TLS verification")
+
+		args := []string{
+			"--mongodb.mygroup.address=mongo-0.mongo:27017",
+			"--mongodb.mygroup.role=primary",
+			"--mongodb.mygroup.replica-set=rs0",
+			"--mongodb.mygroup.username=app",
+			"--mongodb.mygroup.password=file:" + secrets + "//PASSWORD",
+			"--mongodb.mygroup.tls=true",
+			"--mongodb.mygroup.skip-tls-verify=true",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+		assert.Equal(t, "MONGODB", checkers[0].Checker.Type())
+		assert.Equal(t, "mongo-0.mongo:27017", checkers[0].Checker.Address())
+	})
+
+	t.Run("Invalid MongoDB Role", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		mongoGroup := df.Group("mongodb")
+		mongoGroup.String("address", "", "MongoDB target address")
+		mongoGroup.String("role", "any", "Required member state")
+
+		args := []string{
+			"--mongodb.mygroup.address=localhost:27017",
+			"--mongodb.mygroup.role=arbiter",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.EqualError(t, err, "invalid \"--mongodb.mygroup.role\": must be any, primary or secondary: \"arbiter\"")
+	})
+
+	t.Run("MongoDB Password Without Username", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		mongoGroup := df.Group("mongodb")
+		mongoGroup.String("address", "", "MongoDB target address")
+		mongoGroup.String("username", "", "Username")
+		mongoGroup.String("password", "", "Password")
+
+		args := []string{
+			"--mongodb.mygroup.address=localhost:27017",
+			"--mongodb.mygroup.password=secret",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.EqualError(t, err, "invalid \"--mongodb.mygroup.username\": username is required when a password is set")
+	})
+
+	t.Run("Invalid MongoDB Auth Mechanism", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		mongoGroup := df.Group("mongodb")
+		mongoGroup.String("address", "", "MongoDB target address")
+		mongoGroup.String("username", "", "Username")
+		mongoGroup.String("auth-mechanism", "SCRAM-SHA-256", "Authentication mechanism")
+
+		args := []string{
+			"--mongodb.mygroup.address=localhost:27017",
+			"--mongodb.mygroup.username=app",
+			"--mongodb.mygroup.auth-mechanism=MONGODB-X509",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.EqualError(t, err, "invalid \"--mongodb.mygroup.auth-mechanism\": must be SCRAM-SHA-256 or SCRAM-SHA-1: \"MONGODB-X509\"")
+	})
+
+	t.Run("MongoDB Skip TLS Verify Without TLS", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		mongoGroup := df.Group("mongodb")
+		mongoGroup.String("address", "", "MongoDB target address")
+		mongoGroup.Bool("tls", false, "Connect with TLS")
+		mongoGroup.Bool("skip-tls-verify", false, "Skip TLS verification")
+
+		args := []string{
+			"--mongodb.mygroup.address=localhost:27017",
+			"--mongodb.mygroup.skip-tls-verify=true",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.EqualError(t, err, "invalid \"--mongodb.mygroup.skip-tls-verify\": requires \"--mongodb.mygroup.tls\"")
+	})
+
+	t.Run("Valid Kafka Checker", func(t *testing.T) {
+		t.Parallel()
+
+		secrets := filepath.Join(t.TempDir(), "kafka.env")
+		assert.NoError(t, os.WriteFile(secrets, []byte("PASSWORD=secret\n"), 0o600))
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		kafkaGroup := df.Group("kafka")
+		kafkaGroup.String("address", "", "Kafka broker address")
+		kafkaGroup.Duration("timeout", 2*time.Second, "Timeout")
+		kafkaGroup.String("topic", "", "Topic")
+		kafkaGroup.String("username", "", "Username")
+		kafkaGroup.String("password", "", "Password")
+		kafkaGroup.Bool("tls", false, "Connect with TLS")
+		kafkaGroup.Bool("skip-tls-verify", false, "Skip TLS verification")
+		kafkaGroup.String("ca-file", "", "CA file")
+
+		args := []string{
+			"--kafka.mygroup.address=kafka-0.kafka:9093",
+			"--kafka.mygroup.topic=orders",
+			"--kafka.mygroup.username=app",
+			"--kafka.mygroup.password=file:" + secrets + "//PASSWORD",
+			"--kafka.mygroup.tls=true",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+		assert.Equal(t, "KAFKA", checkers[0].Checker.Type())
+		assert.Equal(t, "kafka-0.kafka:9093", checkers[0].Checker.Address())
+	})
+
+	t.Run("Kafka CA File Without TLS", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		kafkaGroup := df.Group("kafka")
+		kafkaGroup.String("address", "", "Kafka broker address")
+		kafkaGroup.Bool("tls", false, "Connect with TLS")
+		kafkaGroup.String("ca-file", "", "CA file")
+
+		args := []string{
+			"--kafka.mygroup.address=localhost:9092",
+			"--kafka.mygroup.ca-file=/etc/ssl/kafka-ca.pem",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.EqualError(t, err, "invalid \"--kafka.mygroup.ca-file\": requires \"--kafka.mygroup.tls\"")
+	})
+
+	t.Run("Valid AMQP Checker", func(t *testing.T) {
+		t.Parallel()
+
+		secrets := filepath.Join(t.TempDir(), "rabbitmq.env")
+		assert.NoError(t, os.WriteFile(secrets, []byte("USER=app\nPASSWORD=secret\n"), 0o600))
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		amqpGroup := df.Group("amqp")
+		amqpGroup.String("address", "", "AMQP broker address")
+		amqpGroup.Duration("timeout", 2*time.Second, "Timeout")
+		amqpGroup.String("username", "", "Username")
+		amqpGroup.String("password", "", "Password")
+		amqpGroup.String("vhost", "/", "Virtual host")
+		amqpGroup.String("queue", "", "Queue")
+
+		args := []string{
+			"--amqp.mygroup.address=rabbitmq:5672",
+			"--amqp.mygroup.username=file:" + secrets + "//USER",
+			"--amqp.mygroup.password=file:" + secrets + "//PASSWORD",
+			"--amqp.mygroup.vhost=orders",
+			"--amqp.mygroup.queue=invoices",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+		assert.Equal(t, "AMQP", checkers[0].Checker.Type())
+		assert.Equal(t, "rabbitmq:5672", checkers[0].Checker.Address())
+	})
+
+	t.Run("AMQP Default Credentials Are Not Redacted", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		amqpGroup := df.Group("amqp")
+		amqpGroup.String("address", "", "AMQP broker address")
+		amqpGroup.String("username", "", "Username")
+		amqpGroup.String("password", "", "Password")
+
+		args := []string{
+			"--amqp.mygroup.address=rabbitmq:5672",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+		assert.Equal(t, "user guest", redact.String("user guest"))
+	})
+
+	t.Run("Invalid AMQP Credentials Variable", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		amqpGroup := df.Group("amqp")
+		amqpGroup.String("address", "", "AMQP broker address")
+		amqpGroup.String("username", "", "Username")
+		amqpGroup.String("password", "", "Password")
+
+		args := []string{
+			"--amqp.mygroup.address=rabbitmq:5672",
+			"--amqp.mygroup.password=file:/nonexistent/rabbitmq.env//PASSWORD",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid \"--amqp.mygroup.password\": failed to resolve variable: ")
+	})
+
+	t.Run("AMQP Queue Name Too Long", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		amqpGroup := df.Group("amqp")
+		amqpGroup.String("address", "", "AMQP broker address")
+		amqpGroup.String("queue", "", "Queue")
+
+		args := []string{
+			"--amqp.mygroup.address=rabbitmq:5672",
+			"--amqp.mygroup.queue=" + strings.Repeat("q", 256),
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.EqualError(t, err, "invalid \"--amqp.mygroup.queue\": must be at most 255 bytes")
+	})
+
+	t.Run("Valid MQTT Checker", func(t *testing.T) {
+		t.Parallel()
+
+		secrets := filepath.Join(t.TempDir(), "mosquitto.env")
+		assert.NoError(t, os.WriteFile(secrets, []byte("PASSWORD=secret\n"), 0o600))
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		mqttGroup := df.Group("mqtt")
+		mqttGroup.String("address", "", "MQTT broker URL")
+		mqttGroup.Duration("timeout", 2*time.Second, "Timeout")
+		mqttGroup.String("protocol-version", "3.1.1", "Protocol version")
+		mqttGroup.String("client-id", "", "Client identifier")
+		mqttGroup.String("username", "", "Username")
+		mqttGroup.String("password", "", "Password")
+		mqttGroup.Bool("skip-tls-verify", false, "Skip TLS verification")
+		mqttGroup.String("topic", "", "Topic")
+
+		args := []string{
+			"--mqtt.mygroup.address=mqtts://emqx:8883",
+			"--mqtt.mygroup.protocol-version=5",
+			"--mqtt.mygroup.client-id=ingest-probe",
+			"--mqtt.mygroup.username=device",
+			"--mqtt.mygroup.password=file:" + secrets + "//PASSWORD",
+			"--mqtt.mygroup.skip-tls-verify=true",
+			"--mqtt.mygroup.topic=health/portpatrol",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+		assert.Equal(t, "MQTT", checkers[0].Checker.Type())
+		assert.Equal(t, "mqtts://emqx:8883", checkers[0].Checker.Address())
+	})
+
+	t.Run("Invalid MQTT Protocol Version", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		mqttGroup := df.Group("mqtt")
+		mqttGroup.String("address", "", "MQTT broker URL")
+		mqttGroup.String("protocol-version", "3.1.1", "Protocol version")
+
+		args := []string{
+			"--mqtt.mygroup.address=mqtt://mosquitto:1883",
+			"--mqtt.mygroup.protocol-version=3.1",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.EqualError(t, err, "invalid \"--mqtt.mygroup.protocol-version\": must be 3.1.1 or 5: \"3.1\"")
+	})
+
+	t.Run("Invalid MQTT CA File", func(t *testing.T) {
+		t.Parallel()
+
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		assert.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		mqttGroup := df.Group("mqtt")
+		mqttGroup.String("address", "", "MQTT broker URL")
+		mqttGroup.String("ca-file", "", "CA file")
+
+		args := []string{
+			"--mqtt.mygroup.address=mqtts://emqx:8883",
+			"--mqtt.mygroup.ca-file=" + caFile,
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.EqualError(t, err, fmt.Sprintf("invalid \"--mqtt.mygroup.ca-file\": no PEM encoded certificates found in %q", caFile))
+	})
+
+	t.Run("MQTT TLS Settings Without mqtts", func(t *testing.T) {
+		t.Parallel()
+
+		for property, value := range map[string]string{
+			"skip-tls-verify": "true",
+			"ca-file":         "/etc/ssl/mqtt-ca.pem",
+		} {
+			df := dynflags.New(dynflags.ContinueOnError)
+			mqttGroup := df.Group("mqtt")
+			mqttGroup.String("address", "", "MQTT broker URL")
+			mqttGroup.Bool("skip-tls-verify", false, "Skip TLS verification")
+			mqttGroup.String("ca-file", "", "CA file")
+
+			args := []string{
+				"--mqtt.mygroup.address=mqtt://mosquitto:1883",
+				"--mqtt.mygroup." + property + "=" + value,
+			}
+			err := df.Parse(args)
+			assert.NoError(t, err)
+
+			_, err = factory.BuildCheckers(df, 2*time.Second)
+			assert.EqualError(t, err, "invalid \"--mqtt.mygroup."+property+"\": requires an mqtts:// address")
+		}
+	})
+
+	t.Run("MQTT Password Without Username", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		mqttGroup := df.Group("mqtt")
+		mqttGroup.String("address", "", "MQTT broker URL")
+		mqttGroup.String("username", "", "Username")
+		mqttGroup.String("password", "", "Password")
+
+		args := []string{
+			"--mqtt.mygroup.address=mqtt://mosquitto:1883",
+			"--mqtt.mygroup.password=secret",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.EqualError(t, err, "invalid \"--mqtt.mygroup.username\": username is required when a password is set")
+	})
+
+	t.Run("MQTT Topic With Wildcard", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		mqttGroup := df.Group("mqtt")
+		mqttGroup.String("address", "", "MQTT broker URL")
+		mqttGroup.String("topic", "", "Topic")
+
+		args := []string{
+			"--mqtt.mygroup.address=mqtt://mosquitto:1883",
+			"--mqtt.mygroup.topic=sensors/#",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.EqualError(t, err, "invalid \"--mqtt.mygroup.topic\": must not contain wildcards: \"sensors/#\"")
+	})
+
+	t.Run("Valid NATS Checker", func(t *testing.T) {
+		t.Parallel()
+
+		secrets := filepath.Join(t.TempDir(), "nats.env")
+		assert.NoError(t, os.WriteFile(secrets, []byte("TOKEN=s3cr3t\n"), 0o600))
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		natsGroup := df.Group("nats")
+		natsGroup.String("address", "", "NATS server address")
+		natsGroup.Duration("timeout", 2*time.Second, "Timeout")
+		natsGroup.String("username", "", "Username")
+		natsGroup.String("password", "", "Password")
+		natsGroup.String("token", "", "Token")
+		natsGroup.Bool("jetstream", false, "Require JetStream")
+		natsGroup.String("stream", "", "Stream")
+		natsGroup.Bool("tls", false, "Connect with TLS")
+		natsGroup.Bool("skip-tls-verify", false, "Skip TLS verification")
+		natsGroup.String("ca-file", "", "CA file")
+
+		args := []string{
+			"--nats.mygroup.address=nats://nats:4222",
+			"--nats.mygroup.token=file:" + secrets + "//TOKEN",
+			"--nats.mygroup.jetstream=true",
+			"--nats.mygroup.stream=ORDERS",
+			"--nats.mygroup.tls=true",
+			"--nats.mygroup.skip-tls-verify=true",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		checkers, err := factory.BuildCheckers(df, 2*time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, checkers, 1)
+		assert.Equal(t, "NATS", checkers[0].Checker.Type())
+		assert.Equal(t, "nats://nats:4222", checkers[0].Checker.Address())
+	})
+
+	t.Run("NATS Token With Credentials", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		natsGroup := df.Group("nats")
+		natsGroup.String("address", "", "NATS server address")
+		natsGroup.String("username", "", "Username")
+		natsGroup.String("password", "", "Password")
+		natsGroup.String("token", "", "Token")
+
+		args := []string{
+			"--nats.mygroup.address=nats:4222",
+			"--nats.mygroup.username=app",
+			"--nats.mygroup.token=s3cr3t",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.EqualError(t, err, "invalid \"--nats.mygroup.token\": cannot be combined with username and password")
+	})
+
+	t.Run("Invalid NATS Stream Name", func(t *testing.T) {
+		t.Parallel()
+
+		df := dynflags.New(dynflags.ContinueOnError)
+		natsGroup := df.Group("nats")
+		natsGroup.String("address", "", "NATS server address")
+		natsGroup.String("stream", "", "Stream")
+
+		args := []string{
+			"--nats.mygroup.address=nats:4222",
+			"--nats.mygroup.stream=orders.eu",
+		}
+		err := df.Parse(args)
+		assert.NoError(t, err)
+
+		_, err = factory.BuildCheckers(df, 2*time.Second)
+		assert.EqualError(t, err, "invalid \"--nats.mygroup.stream\": must not contain whitespace, \".\", \"*\" or \">\": \"orders.eu\"")
+	})
+
 	t.Run("Invalid ICMP Checker", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,73 @@
+package factory
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/containeroo/dynflags"
+)
+
+// propertyGroup wraps a parsed group and records which properties were read while building its checker,
+// so properties that no checker option consumes are reported instead of being silently ignored. Reading a
+// property does not mean it takes effect: properties that only apply together with another one (e.g. a
+// Content-Type without a body) must be rejected explicitly where they are built.
+type propertyGroup struct {
+	*dynflags.ParsedGroup
+	consumed map[string]struct{}
+}
+
+// newPropertyGroup creates a propertyGroup for group.
+func newPropertyGroup(group *dynflags.ParsedGroup) *propertyGroup {
+	return &propertyGroup{ParsedGroup: group, consumed: make(map[string]struct{})}
+}
+
+func (g *propertyGroup) GetString(name string) (string, error) {
+	g.consume(name)
+	return g.ParsedGroup.GetString(name)
+}
+
+func (g *propertyGroup) GetInt(name string) (int, error) {
+	g.consume(name)
+	return g.ParsedGroup.GetInt(name)
+}
+
+func (g *propertyGroup) GetBool(name string) (bool, error) {
+	g.consume(name)
+	return g.ParsedGroup.GetBool(name)
+}
+
+func (g *propertyGroup) GetDuration(name string) (time.Duration, error) {
+	g.consume(name)
+	return g.ParsedGroup.GetDuration(name)
+}
+
+func (g *propertyGroup) GetStringSlices(name string) ([]string, error) {
+	g.consume(name)
+	return g.ParsedGroup.GetStringSlices(name)
+}
+
+// consume marks the property name as read.
+func (g *propertyGroup) consume(name string) {
+	g.consumed[name] = struct{}{}
+}
+
+// unconsumed returns the sorted names of the parsed properties that were never read.
+func (g *propertyGroup) unconsumed() []string {
+	var names []string
+	for name := range g.Values {
+		if _, ok := g.consumed[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// validateConsumed returns an error for the first parsed property the checker type does not use.
+func validateConsumed(parentName string, group *propertyGroup) error {
+	if names := group.unconsumed(); len(names) > 0 {
+		return fmt.Errorf("invalid \"--%s.%s.%s\": not supported by %s checkers", parentName, group.Name, names[0], parentName)
+	}
+	return nil
+}
@@ -3,10 +3,22 @@ package logging
 import (
 	"io"
 	"log/slog"
+
+	"github.com/containeroo/portpatrol/internal/redact"
 )
 
 // SetupLogger configures the application logger.
 func SetupLogger(version string, output io.Writer) *slog.Logger {
-	logger := slog.New(slog.NewTextHandler(output, &slog.HandlerOptions{}))
+	logger := slog.New(slog.NewTextHandler(output, &slog.HandlerOptions{
+		ReplaceAttr: redactAttr,
+	}))
 	return logger.With(slog.String("version", version))
 }
+
+// redactAttr masks sensitive values in string attributes, including the log message.
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() == slog.KindString {
+		return slog.String(a.Key, redact.String(a.Value.String()))
+	}
+	return a
+}
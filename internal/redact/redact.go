@@ -0,0 +1,109 @@
+// Package redact masks credentials and other sensitive values before they are written to logs or output.
+package redact
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mask is the placeholder that replaces sensitive values.
+const Mask string = "xxxxx"
+
+// minSecretLength is the minimum length of a registered secret. Shorter values would
+// mask unrelated parts of the output.
+const minSecretLength int = 4
+
+var (
+	// urlUserinfoRegex matches the userinfo part of URLs (e.g. "https://user:password@").
+	urlUserinfoRegex = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)([^/?#@\s]+)@`)
+	// sensitiveHeaderRegex matches the values of sensitive headers (e.g. "Authorization: Bearer <token>"), keeping
+	// the authorization scheme. Scheme names elsewhere in the text are not credentials and are left alone.
+	sensitiveHeaderRegex = regexp.MustCompile(
+		`(?i)\b((?:proxy-)?authorization|(?:set-)?cookie|x-api-key|x-auth-token)(\s*[:=]\s*)((?:bearer|basic|token)\s+)?[^\s,;"']+`,
+	)
+)
+
+// sensitiveHeaders lists HTTP headers whose values are always treated as secrets.
+var sensitiveHeaders = map[string]struct{}{
+	"authorization":       {},
+	"proxy-authorization": {},
+	"cookie":              {},
+	"set-cookie":          {},
+	"x-api-key":           {},
+	"x-auth-token":        {},
+}
+
+var (
+	mu      sync.RWMutex
+	secrets = make(map[string]struct{})
+)
+
+// Register marks the given values as sensitive. Every occurrence is masked by String.
+func Register(values ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, v := range values {
+		if len(v) < minSecretLength {
+			continue
+		}
+		secrets[v] = struct{}{}
+	}
+}
+
+// IsSensitiveHeader reports whether the value of the given HTTP header must be treated as a secret.
+func IsSensitiveHeader(name string) bool {
+	_, ok := sensitiveHeaders[strings.ToLower(strings.TrimSpace(name))]
+	return ok
+}
+
+// String masks registered secrets, passwords in URLs and sensitive header values in s.
+func String(s string) string {
+	mu.RLock()
+	registered := make([]string, 0, len(secrets))
+	for secret := range secrets {
+		registered = append(registered, secret)
+	}
+	mu.RUnlock()
+
+	// Replace longer secrets first so a secret containing another one is fully masked
+	sort.Slice(registered, func(i, j int) bool { return len(registered[i]) > len(registered[j]) })
+	for _, secret := range registered {
+		s = strings.ReplaceAll(s, secret, Mask)
+	}
+
+	s = urlUserinfoRegex.ReplaceAllStringFunc(s, redactUserinfo)
+	s = sensitiveHeaderRegex.ReplaceAllString(s, "${1}${2}${3}"+Mask)
+
+	return s
+}
+
+// Error wraps err so that its message is redacted. The original error remains available through errors.Unwrap.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &redactedError{err: err}
+}
+
+// redactedError is an error whose message has been redacted.
+type redactedError struct {
+	err error
+}
+
+func (e *redactedError) Error() string { return String(e.err.Error()) }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// redactUserinfo masks the password of a matched URL userinfo. Userinfo without a password is
+// considered a token and masked entirely.
+func redactUserinfo(match string) string {
+	parts := urlUserinfoRegex.FindStringSubmatch(match)
+	scheme, userinfo := parts[1], parts[2]
+
+	if user, _, found := strings.Cut(userinfo, ":"); found {
+		return scheme + user + ":" + Mask + "@"
+	}
+	return scheme + Mask + "@"
+}
@@ -3,6 +3,7 @@ package testutils
 import (
 	"context"
 	"net"
+	"sync"
 	"time"
 )
 
@@ -12,7 +13,8 @@ type MockProtocol struct {
 	ValidateReplyFunc func(reply []byte, identifier, sequence uint16) error
 	NetworkFunc       func() string
 	ListenPacketFunc  func(ctx context.Context, network, address string) (net.PacketConn, error)
-	SetDeadlineFunc   func(t time.Time) error
+	PrivilegedFunc    func() bool
+	SetPrivilegedFunc func(privileged bool)
 }
 
 // MakeRequest is a mock implementation of the Protocol.MakeRequest method.
@@ -47,15 +49,24 @@ func (m *MockProtocol) ListenPacket(ctx context.Context, network, address string
 	return nil, nil
 }
 
-// SetDeadline is a mock implementation of the Protocol.SetDeadline method
-func (m *MockProtocol) SetDeadline(t time.Time) error {
-	if m.SetDeadlineFunc != nil {
-		return m.SetDeadlineFunc(t)
+// Privileged is a mock implementation of the Protocol.Privileged method.
+func (m *MockProtocol) Privileged() bool {
+	if m.PrivilegedFunc != nil {
+		return m.PrivilegedFunc()
+	}
+	return true
+}
+
+// SetPrivileged is a mock implementation of the Protocol.SetPrivileged method.
+func (m *MockProtocol) SetPrivileged(privileged bool) {
+	if m.SetPrivilegedFunc != nil {
+		m.SetPrivilegedFunc(privileged)
 	}
-	return nil
 }
 
 // MockPacketConn is a mock implementation of net.PacketConn for testing purposes.
+// Without WriteToFunc and ReadFromFunc it behaves like a loopback: written packets are read back,
+// and ReadFrom blocks until a packet was written or the connection is closed.
 type MockPacketConn struct {
 	SetDeadlineFunc      func(t time.Time) error
 	SetReadDeadlineFunc  func(t time.Time) error
@@ -65,6 +76,19 @@ type MockPacketConn struct {
 	CloseFunc            func() error
 	LocalAddrFunc        func() net.Addr
 	RemoteAddrFunc       func() net.Addr
+
+	initOnce  sync.Once
+	closeOnce sync.Once
+	packets   chan []byte
+	closed    chan struct{}
+}
+
+// init creates the loopback channels on first use, so the zero value is ready to use.
+func (m *MockPacketConn) init() {
+	m.initOnce.Do(func() {
+		m.packets = make(chan []byte, 16)
+		m.closed = make(chan struct{})
+	})
 }
 
 // SetDeadline is a mock implementation of the net.PacketConn.
@@ -96,6 +120,11 @@ func (m *MockPacketConn) WriteTo(b []byte, addr net.Addr) (int, error) {
 	if m.WriteToFunc != nil {
 		return m.WriteToFunc(b, addr)
 	}
+	m.init()
+	select {
+	case m.packets <- append([]byte(nil), b...):
+	default:
+	}
 	return len(b), nil
 }
 
@@ -104,11 +133,19 @@ func (m *MockPacketConn) ReadFrom(b []byte) (int, net.Addr, error) {
 	if m.ReadFromFunc != nil {
 		return m.ReadFromFunc(b)
 	}
-	return 0, nil, nil
+	m.init()
+	select {
+	case packet := <-m.packets:
+		return copy(b, packet), &net.IPAddr{}, nil
+	case <-m.closed:
+		return 0, nil, net.ErrClosed
+	}
 }
 
 // Close is a mock implementation of the net.PacketConn.
 func (m *MockPacketConn) Close() error {
+	m.init()
+	m.closeOnce.Do(func() { close(m.closed) })
 	if m.CloseFunc != nil {
 		return m.CloseFunc()
 	}
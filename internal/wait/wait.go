@@ -22,12 +22,13 @@ func WaitUntilReady(ctx context.Context, interval time.Duration, checker checker
 
 	for {
 		err := checker.Check(ctx)
+		details := checkDetails(checker)
 		if err == nil {
-			logger.Info(fmt.Sprintf("%s is ready ✓", checker.Name()))
+			logger.Info(fmt.Sprintf("%s is ready ✓", checker.Name()), details...)
 			return nil // Successfully connected to the target
 		}
 
-		logger.Warn(fmt.Sprintf("%s is not ready ✗", checker.Name()), slog.String("error", err.Error()))
+		logger.Warn(fmt.Sprintf("%s is not ready ✗", checker.Name()), append([]any{slog.String("error", err.Error())}, details...)...)
 
 		select {
 		case <-time.After(interval):
@@ -40,3 +41,17 @@ func WaitUntilReady(ctx context.Context, interval time.Duration, checker checker
 		}
 	}
 }
+
+// checkDetails returns the details of the most recent check as log arguments, if the checker provides them.
+func checkDetails(chk checker.Checker) []any {
+	detailer, ok := chk.(checker.Detailer)
+	if !ok {
+		return nil
+	}
+
+	var args []any
+	for _, attr := range detailer.Details() {
+		args = append(args, attr)
+	}
+	return args
+}
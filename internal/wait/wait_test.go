@@ -211,3 +211,35 @@ func TestWaitUntilReady_TCPContextCanceled(t *testing.T) {
 		t.Errorf("Expected log to contain %q, got %q", expectedLog, output.String())
 	}
 }
+
+// TestWaitUntilReady_TCPAllAddresses ensures the per-address results are logged.
+func TestWaitUntilReady_TCPAllAddresses(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:9088")
+	if err != nil {
+		t.Fatalf("Failed to create TCP server: %v", err)
+	}
+	defer ln.Close()
+
+	checker, err := checker.NewChecker(checker.TCP, "TCPServer", "127.0.0.1:9088", checker.WithTCPAllAddresses(0))
+	if err != nil {
+		t.Fatalf("Failed to create TCPChecker: %v", err)
+	}
+
+	var output strings.Builder
+	logger := slog.New(slog.NewTextHandler(&output, nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	err = WaitUntilReady(ctx, 100*time.Millisecond, checker, logger)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	expectedLog := `addresses="127.0.0.1=ok"`
+	if !strings.Contains(output.String(), expectedLog) {
+		t.Errorf("Expected log to contain %q, got %q", expectedLog, output.String())
+	}
+}